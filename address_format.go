@@ -0,0 +1,443 @@
+package tripica
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultAddressLineLength is the default maximum number of characters of an address line, which fits
+	// the window of a DIN 5008 envelope in common letter fonts.
+	DefaultAddressLineLength = 35
+	// DefaultAddressLines is the number of lines of the address zone of a DIN 5008 address field.
+	DefaultAddressLines = 6
+	// defaultSenderCountry is the country addresses are considered domestic in, unless specified otherwise.
+	defaultSenderCountry = "DE"
+)
+
+// Possible orders of the postcode and city of an address.
+const (
+	// postcodeBeforeCity is the layout of most European countries, e.g. "75008 PARIS".
+	postcodeBeforeCity placeLayout = iota
+	// postcodeAfterCity is the layout of North American countries, e.g. "NEW YORK 10001".
+	postcodeAfterCity
+	// postcodeOwnLine is the layout of the British Isles, e.g. "LONDON" followed by "SW1A 1AA".
+	postcodeOwnLine
+)
+
+// Possible values of Individual.Gender.
+const (
+	GenderMale   = "MALE"
+	GenderFemale = "FEMALE"
+)
+
+var (
+	// ErrAddressLineTooLong is returned if a line of an address block can't be shortened or wrapped
+	// to the maximum line length.
+	ErrAddressLineTooLong = errors.New("address line too long")
+	// ErrTooManyAddressLines is returned if an address block has more lines than allowed, even without
+	// its optional lines.
+	ErrTooManyAddressLines = errors.New("too many address lines")
+
+	// addressCountries describe how addresses of the countries are formatted. Country lines are written in
+	// German, as required for letters sent from Germany.
+	addressCountries = map[string]addressCountry{
+		"AT": {name: "ÖSTERREICH"}, "BE": {name: "BELGIEN"}, "CA": {name: "KANADA", layout: postcodeAfterCity},
+		"CH": {name: "SCHWEIZ"}, "CZ": {name: "TSCHECHIEN"}, "DE": {name: "DEUTSCHLAND"}, "DK": {name: "DÄNEMARK"},
+		"ES": {name: "SPANIEN"}, "FI": {name: "FINNLAND"}, "FR": {name: "FRANKREICH"},
+		"GB": {name: "VEREINIGTES KÖNIGREICH", layout: postcodeOwnLine}, "GR": {name: "GRIECHENLAND"},
+		"HU": {name: "UNGARN"}, "IE": {name: "IRLAND", layout: postcodeOwnLine}, "IT": {name: "ITALIEN"},
+		"LI": {name: "LIECHTENSTEIN"}, "LU": {name: "LUXEMBURG"}, "NL": {name: "NIEDERLANDE"},
+		"NO": {name: "NORWEGEN"}, "PL": {name: "POLEN"}, "PT": {name: "PORTUGAL"}, "SE": {name: "SCHWEDEN"},
+		"US": {name: "VEREINIGTE STAATEN", layout: postcodeAfterCity},
+	}
+
+	// countryNames maps common country names triPica may contain instead of ISO codes to the latter.
+	countryNames = map[string]string{
+		"DEUTSCHLAND": "DE", "GERMANY": "DE", "ÖSTERREICH": "AT", "AUSTRIA": "AT", "SCHWEIZ": "CH",
+		"SWITZERLAND": "CH",
+	}
+
+	// formsOfAddress map titles which are forms of address, rather than academic titles, to genders.
+	formsOfAddress = map[string]string{
+		"herr": GenderMale, "herrn": GenderMale, "mr": GenderMale, "mr.": GenderMale,
+		"frau": GenderFemale, "mrs": GenderFemale, "mrs.": GenderFemale, "ms": GenderFemale, "ms.": GenderFemale,
+	}
+)
+
+type (
+	// Recipient represents the addressee lines of an address block.
+	Recipient struct {
+		// Salutation is the optional first line, e.g. "Herrn" or "Frau", which is left out if the block is too long.
+		Salutation string
+		// Name is the name of the recipient, e.g. "Dr. Max Mustermann" or the name of a company.
+		Name string
+		// ShortNames replace Name if the latter is too long, the first one which fits being used,
+		// e.g. "Dr. M. Mustermann" followed by "Dr. Mustermann".
+		ShortNames []string
+		// Attention is an additional line for organizations, e.g. "z. Hd. Dr. Max Mustermann", which is
+		// left out if the block is too long.
+		Attention string
+	}
+
+	// AddressBlock represents the lines of the address field of a letter, from the recipient to the country.
+	AddressBlock struct {
+		Lines []string
+	}
+
+	// AddressFormatter formats postal addresses for letters. German addresses are formatted according to
+	// DIN 5008, while addresses abroad follow the format of their country, with the city and country in
+	// capital letters.
+	AddressFormatter struct {
+		maxLineLength int
+		maxLines      int
+		senderCountry string
+	}
+
+	// AddressFormatOption represents a functional option used to configure an AddressFormatter.
+	AddressFormatOption func(*AddressFormatter)
+
+	addressCountry struct {
+		name   string
+		layout placeLayout
+	}
+
+	// placeLayout represents the order of the postcode and city of an address.
+	placeLayout int
+
+	// addressLine is a line of an address block, which is either required, or may be left out. Lines wrapped
+	// from the same line share their group, so that they are left out together.
+	addressLine struct {
+		text     string
+		optional bool
+		group    int
+	}
+)
+
+// WithMaxLineLength limits the number of characters of each line. Non-positive values fall back to the default
+// of 35.
+func WithMaxLineLength(n int) AddressFormatOption {
+	return func(f *AddressFormatter) {
+		if n > 0 {
+			f.maxLineLength = n
+		}
+	}
+}
+
+// WithMaxLines limits the number of lines of an address block. Non-positive values fall back to the default of 6.
+func WithMaxLines(n int) AddressFormatOption {
+	return func(f *AddressFormatter) {
+		if n > 0 {
+			f.maxLines = n
+		}
+	}
+}
+
+// WithSenderCountry sets the ISO 3166 country letters are sent from, which defaults to "DE". Addresses
+// in other countries get a country line.
+func WithSenderCountry(country string) AddressFormatOption {
+	return func(f *AddressFormatter) {
+		if country != "" {
+			f.senderCountry = strings.ToUpper(country)
+		}
+	}
+}
+
+// NewAddressFormatter returns an AddressFormatter configured by the options.
+func NewAddressFormatter(options ...AddressFormatOption) *AddressFormatter {
+	f := &AddressFormatter{
+		maxLineLength: DefaultAddressLineLength,
+		maxLines:      DefaultAddressLines,
+		senderCountry: defaultSenderCountry,
+	}
+
+	for _, option := range options {
+		option(f)
+	}
+
+	return f
+}
+
+// Format returns the address block for the recipient at the address. Lines exceeding the maximum line length
+// are shortened, or wrapped at spaces, and optional lines are left out if the block has too many lines.
+// A ValidationError is returned for incomplete addresses, while ErrAddressLineTooLong and ErrTooManyAddressLines
+// are wrapped if the address doesn't fit.
+//
+//	block, err := tripica.NewAddressFormatter().Format(individual.Recipient(), individual.BillingAddress(now))
+func (f *AddressFormatter) Format(recipient Recipient, address MediumTypeAddress) (*AddressBlock, error) {
+	var errs fieldErrors
+
+	errs.require("name", recipient.Name)
+	errs.require("street1", address.Street1)
+	errs.require("city", address.City)
+
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	var lines []addressLine
+
+	if recipient.Salutation != "" {
+		lines = append(lines, addressLine{text: collapseSpaces(recipient.Salutation), optional: true})
+	}
+
+	name := collapseSpaces(recipient.Name)
+	for _, short := range recipient.ShortNames {
+		if !f.tooLong(name) {
+			break
+		}
+
+		if short = collapseSpaces(short); short != "" {
+			name = short
+		}
+	}
+
+	lines = append(lines, addressLine{text: name})
+
+	if recipient.Attention != "" {
+		lines = append(lines, addressLine{text: collapseSpaces(recipient.Attention), optional: true})
+	}
+
+	for _, street := range []string{address.Street1, address.Street2} {
+		if street = collapseSpaces(street); street != "" {
+			lines = append(lines, addressLine{text: street})
+		}
+	}
+
+	for _, line := range f.placeLines(address) {
+		lines = append(lines, addressLine{text: line})
+	}
+
+	wrapped, err := f.wrap(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.fit(wrapped)
+}
+
+// String returns the lines of the address block, separated by line breaks.
+func (b *AddressBlock) String() string {
+	return strings.Join(b.Lines, "\n")
+}
+
+// Recipient returns the recipient lines for letters to the individual, with a salutation based on their gender,
+// or their title if it's a form of address like "Frau".
+func (i *Individual) Recipient() Recipient {
+	title := i.academicTitle()
+
+	r := Recipient{
+		Name: joinNonEmpty(title, i.Name, i.LastName),
+		ShortNames: []string{
+			joinNonEmpty(title, initials(i.Name), i.LastName),
+			joinNonEmpty(title, i.LastName),
+			i.LastName,
+		},
+	}
+
+	switch i.gender() {
+	case GenderMale:
+		r.Salutation = "Herrn"
+	case GenderFemale:
+		r.Salutation = "Frau"
+	}
+
+	return r
+}
+
+// LetterSalutation returns the salutation opening a letter to the individual, e.g.
+// "Sehr geehrte Frau Dr. Mustermann,", or "Guten Tag Max Mustermann," if their gender is unknown.
+func (i *Individual) LetterSalutation() string {
+	switch i.gender() {
+	case GenderMale:
+		return fmt.Sprintf("Sehr geehrter Herr %s,", joinNonEmpty(i.academicTitle(), i.LastName))
+	case GenderFemale:
+		return fmt.Sprintf("Sehr geehrte Frau %s,", joinNonEmpty(i.academicTitle(), i.LastName))
+	default:
+		return fmt.Sprintf("Guten Tag %s,", joinNonEmpty(i.Name, i.LastName))
+	}
+}
+
+// Recipient returns the recipient lines for letters to the organization, addressed to the attention
+// of its contact person, if there is one.
+func (o *Organization) Recipient() Recipient {
+	r := Recipient{Name: o.Name}
+	if o.TradingName != "" && o.TradingName != o.Name {
+		r.ShortNames = []string{o.TradingName}
+	}
+
+	if p := o.ContactPerson(); p != nil && p.FullName() != "" {
+		r.Attention = "z. Hd. " + p.FullName()
+	}
+
+	return r
+}
+
+// gender returns the gender of the individual, falling back to the one implied by their title.
+func (i *Individual) gender() string {
+	switch {
+	case strings.EqualFold(i.Gender, GenderMale):
+		return GenderMale
+	case strings.EqualFold(i.Gender, GenderFemale):
+		return GenderFemale
+	default:
+		return formsOfAddress[strings.ToLower(strings.TrimSpace(i.Title))]
+	}
+}
+
+// academicTitle returns the title of the individual, unless it's a form of address.
+func (i *Individual) academicTitle() string {
+	if _, ok := formsOfAddress[strings.ToLower(strings.TrimSpace(i.Title))]; ok {
+		return ""
+	}
+
+	return strings.TrimSpace(i.Title)
+}
+
+// placeLines returns the lines with the postcode, city and country of the address.
+func (f *AddressFormatter) placeLines(address MediumTypeAddress) []string {
+	code := countryCode(address.Country)
+	if code == "" {
+		code = f.senderCountry
+	}
+
+	postcode := collapseSpaces(address.Postcode)
+	city := collapseSpaces(address.City)
+
+	if code == f.senderCountry {
+		return []string{joinNonEmpty(postcode, city)}
+	}
+
+	country, ok := addressCountries[code]
+	if !ok {
+		country = addressCountry{name: strings.ToUpper(collapseSpaces(address.Country))}
+	}
+
+	city = strings.ToUpper(city)
+
+	var lines []string
+
+	switch country.layout {
+	case postcodeAfterCity:
+		lines = []string{joinNonEmpty(city, postcode)}
+	case postcodeOwnLine:
+		lines = []string{city, strings.ToUpper(postcode)}
+	default:
+		lines = []string{joinNonEmpty(postcode, city)}
+	}
+
+	return append(lines, country.name)
+}
+
+// wrap wraps lines exceeding the maximum line length at spaces.
+func (f *AddressFormatter) wrap(lines []addressLine) ([]addressLine, error) {
+	wrapped := make([]addressLine, 0, len(lines))
+
+	for group, line := range lines {
+		if line.text == "" {
+			continue
+		}
+
+		current := ""
+
+		for _, word := range strings.Fields(line.text) {
+			if f.tooLong(word) {
+				return nil, fmt.Errorf("%w: %q exceeds %d characters", ErrAddressLineTooLong, word, f.maxLineLength)
+			}
+
+			if current != "" && f.tooLong(current+" "+word) {
+				wrapped = append(wrapped, addressLine{text: current, optional: line.optional, group: group})
+				current = word
+
+				continue
+			}
+
+			current = joinNonEmpty(current, word)
+		}
+
+		wrapped = append(wrapped, addressLine{text: current, optional: line.optional, group: group})
+	}
+
+	return wrapped, nil
+}
+
+// fit leaves out optional lines, starting with the first one, until the block has at most the maximum number
+// of lines.
+func (f *AddressFormatter) fit(lines []addressLine) (*AddressBlock, error) {
+	excess := len(lines) - f.maxLines
+	dropped := map[int]bool{}
+
+	for _, line := range lines {
+		if excess <= 0 {
+			break
+		}
+
+		if line.optional && !dropped[line.group] {
+			dropped[line.group] = true
+
+			for _, other := range lines {
+				if other.group == line.group {
+					excess--
+				}
+			}
+		}
+	}
+
+	if excess > 0 {
+		return nil, fmt.Errorf("%w: %d lines exceed the maximum of %d",
+			ErrTooManyAddressLines, f.maxLines+excess, f.maxLines)
+	}
+
+	block := &AddressBlock{Lines: make([]string, 0, len(lines))}
+
+	for _, line := range lines {
+		if !dropped[line.group] {
+			block.Lines = append(block.Lines, line.text)
+		}
+	}
+
+	return block, nil
+}
+
+func (f *AddressFormatter) tooLong(s string) bool {
+	return utf8.RuneCountInString(s) > f.maxLineLength
+}
+
+// countryCode returns the ISO 3166 code of the country, which may be a code or a common name.
+func countryCode(country string) string {
+	country = strings.ToUpper(collapseSpaces(country))
+	if code, ok := countryNames[country]; ok {
+		return code
+	}
+
+	return country
+}
+
+// initials abbreviates the given names, e.g. "Hans-Peter Max" to "H.-P. M.".
+func initials(names string) string {
+	abbreviated := make([]string, 0, 2)
+
+	for _, name := range strings.Fields(names) {
+		parts := strings.Split(name, "-")
+		for i, part := range parts {
+			if r, _ := utf8.DecodeRuneInString(part); r != utf8.RuneError {
+				parts[i] = string(r) + "."
+			}
+		}
+
+		abbreviated = append(abbreviated, strings.Join(parts, "-"))
+	}
+
+	return strings.Join(abbreviated, " ")
+}
+
+// collapseSpaces trims the value, and collapses whitespace within it.
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func joinNonEmpty(parts ...string) string {
+	return collapseSpaces(strings.Join(parts, " "))
+}
@@ -0,0 +1,259 @@
+package tripica
+
+import (
+	goerrors "errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressFormatter_Format(t *testing.T) {
+	berlin := MediumTypeAddress{Street1: "Musterstraße 1", Postcode: "10115", City: "Berlin", Country: "DE"}
+	max := Recipient{Salutation: "Herrn", Name: "Max Mustermann"}
+
+	tests := []struct {
+		name      string
+		options   []AddressFormatOption
+		recipient Recipient
+		address   MediumTypeAddress
+		want      []string
+		wantErr   error
+	}{
+		{
+			name:      "domestic",
+			recipient: max,
+			address:   berlin,
+			want:      []string{"Herrn", "Max Mustermann", "Musterstraße 1", "10115 Berlin"},
+		},
+		{
+			name:      "domestic by country name, collapsing spaces",
+			recipient: Recipient{Name: "  Max   Mustermann "},
+			address: MediumTypeAddress{
+				Street1: "Musterstraße  1", Street2: "Hinterhaus", Postcode: "10115", City: " Berlin", Country: "Germany",
+			},
+			want: []string{"Max Mustermann", "Musterstraße 1", "Hinterhaus", "10115 Berlin"},
+		},
+		{
+			name:      "domestic without a country",
+			recipient: max,
+			address:   MediumTypeAddress{Street1: "Musterstraße 1", Postcode: "10115", City: "Berlin"},
+			want:      []string{"Herrn", "Max Mustermann", "Musterstraße 1", "10115 Berlin"},
+		},
+		{
+			name:      "abroad",
+			recipient: max,
+			address:   MediumTypeAddress{Street1: "8 Rue Royale", Postcode: "75008", City: "Paris", Country: "FR"},
+			want:      []string{"Herrn", "Max Mustermann", "8 Rue Royale", "75008 PARIS", "FRANKREICH"},
+		},
+		{
+			name:      "postcode on its own line in GB",
+			recipient: max,
+			address:   MediumTypeAddress{Street1: "10 Downing Street", Postcode: "sw1a 2aa", City: "London", Country: "gb"},
+			want: []string{
+				"Herrn", "Max Mustermann", "10 Downing Street", "LONDON", "SW1A 2AA", "VEREINIGTES KÖNIGREICH",
+			},
+		},
+		{
+			name:      "postcode after the city in the US",
+			recipient: max,
+			address:   MediumTypeAddress{Street1: "350 Fifth Avenue", Postcode: "10118", City: "New York", Country: "US"},
+			want:      []string{"Herrn", "Max Mustermann", "350 Fifth Avenue", "NEW YORK 10118", "VEREINIGTE STAATEN"},
+		},
+		{
+			name:      "unknown countries are written as they are",
+			recipient: max,
+			address:   MediumTypeAddress{Street1: "Main Street 1", Postcode: "1234", City: "Poseidonis", Country: "Atlantis"},
+			want:      []string{"Herrn", "Max Mustermann", "Main Street 1", "1234 POSEIDONIS", "ATLANTIS"},
+		},
+		{
+			name:      "sender country",
+			options:   []AddressFormatOption{WithSenderCountry("at")},
+			recipient: max,
+			address:   berlin,
+			want:      []string{"Herrn", "Max Mustermann", "Musterstraße 1", "10115 BERLIN", "DEUTSCHLAND"},
+		},
+		{
+			name: "long lines are wrapped at spaces",
+			recipient: Recipient{
+				Name:      "Muster GmbH",
+				Attention: "z. Hd. Prof. Dr. Hildegard Müller-Lüdenscheidt",
+			},
+			address: berlin,
+			want: []string{
+				"Muster GmbH", "z. Hd. Prof. Dr. Hildegard", "Müller-Lüdenscheidt", "Musterstraße 1", "10115 Berlin",
+			},
+		},
+		{
+			name:    "optional lines are left out, starting with the first one",
+			options: []AddressFormatOption{WithMaxLines(5)},
+			recipient: Recipient{
+				Salutation: "Firma",
+				Name:       "Muster GmbH",
+				Attention:  "z. Hd. Max Mustermann",
+			},
+			address: MediumTypeAddress{
+				Street1: "Musterstraße 1", Street2: "Hinterhaus", Postcode: "10115", City: "Berlin",
+			},
+			want: []string{"Muster GmbH", "z. Hd. Max Mustermann", "Musterstraße 1", "Hinterhaus", "10115 Berlin"},
+		},
+		{
+			name:    "wrapped optional lines are left out together",
+			options: []AddressFormatOption{WithMaxLines(4)},
+			recipient: Recipient{
+				Name:      "Muster GmbH",
+				Attention: "z. Hd. Prof. Dr. Hildegard Müller-Lüdenscheidt",
+			},
+			address: berlin,
+			want:    []string{"Muster GmbH", "Musterstraße 1", "10115 Berlin"},
+		},
+		{
+			name:      "required lines exceeding the maximum",
+			options:   []AddressFormatOption{WithMaxLines(4)},
+			recipient: Recipient{Salutation: "Herrn", Name: "Max Mustermann"},
+			address:   MediumTypeAddress{Street1: "8 Rue Royale", Street2: "Bâtiment B", City: "Paris", Country: "FR"},
+			wantErr:   ErrTooManyAddressLines,
+		},
+		{
+			name:      "words exceeding the maximum line length",
+			options:   []AddressFormatOption{WithMaxLineLength(10)},
+			recipient: Recipient{Name: "Max Mustermann"},
+			address:   berlin,
+			wantErr:   ErrAddressLineTooLong,
+		},
+		{
+			name:    "the first short name which fits replaces a long name",
+			options: []AddressFormatOption{WithMaxLineLength(20)},
+			recipient: Recipient{
+				Name:       "Dr. Hans-Peter Mustermann",
+				ShortNames: []string{"Dr. H.-P. Mustermann", "Dr. Mustermann"},
+			},
+			address: berlin,
+			want:    []string{"Dr. H.-P. Mustermann", "Musterstraße 1", "10115 Berlin"},
+		},
+		{
+			name:    "later short names are tried if earlier ones are too long",
+			options: []AddressFormatOption{WithMaxLineLength(18)},
+			recipient: Recipient{
+				Name:       "Dr. Hans-Peter Mustermann",
+				ShortNames: []string{"Dr. H.-P. Mustermann", " ", "Dr. Mustermann"},
+			},
+			address: berlin,
+			want:    []string{"Dr. Mustermann", "Musterstraße 1", "10115 Berlin"},
+		},
+		{
+			name:    "the last short name is wrapped if none fits",
+			options: []AddressFormatOption{WithMaxLineLength(16)},
+			recipient: Recipient{
+				Name:       "Dr. Hans-Peter Mustermann",
+				ShortNames: []string{"Dr. H.-P. Mustermann"},
+			},
+			address: berlin,
+			want:    []string{"Dr. H.-P.", "Mustermann", "Musterstraße 1", "10115 Berlin"},
+		},
+		{
+			name:      "short names aren't used for names which fit",
+			recipient: Recipient{Name: "Max Mustermann", ShortNames: []string{"Mustermann"}},
+			address:   berlin,
+			want:      []string{"Max Mustermann", "Musterstraße 1", "10115 Berlin"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			block, err := NewAddressFormatter(tt.options...).Format(tt.recipient, tt.address)
+			if tt.wantErr != nil {
+				assert.True(t, goerrors.Is(err, tt.wantErr), err)
+				assert.Nil(t, block)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, block.Lines)
+		})
+	}
+}
+
+func TestAddressFormatter_Format_Incomplete(t *testing.T) {
+	block, err := NewAddressFormatter().Format(Recipient{}, MediumTypeAddress{Postcode: "10115"})
+
+	assert.Nil(t, block)
+
+	var validationErr *ValidationError
+	require.True(t, goerrors.As(err, &validationErr))
+
+	fields := make([]string, 0, len(validationErr.Fields))
+	for _, field := range validationErr.Fields {
+		fields = append(fields, field.Field)
+	}
+
+	assert.Equal(t, []string{"name", "street1", "city"}, fields)
+}
+
+func TestIndividual_Recipient(t *testing.T) {
+	tests := []struct {
+		name       string
+		individual Individual
+		want       Recipient
+		salutation string
+	}{
+		{
+			name:       "gender",
+			individual: Individual{Title: "Dr.", Name: "Hans-Peter Max", LastName: "Mustermann", Gender: "male"},
+			want: Recipient{
+				Salutation: "Herrn",
+				Name:       "Dr. Hans-Peter Max Mustermann",
+				ShortNames: []string{"Dr. H.-P. M. Mustermann", "Dr. Mustermann", "Mustermann"},
+			},
+			salutation: "Sehr geehrter Herr Dr. Mustermann,",
+		},
+		{
+			name:       "form of address",
+			individual: Individual{Title: "Frau", Name: "Erika", LastName: "Mustermann"},
+			want: Recipient{
+				Salutation: "Frau",
+				Name:       "Erika Mustermann",
+				ShortNames: []string{"E. Mustermann", "Mustermann", "Mustermann"},
+			},
+			salutation: "Sehr geehrte Frau Mustermann,",
+		},
+		{
+			name:       "unknown gender",
+			individual: Individual{Name: "Kim", LastName: "Mustermann"},
+			want: Recipient{
+				Name:       "Kim Mustermann",
+				ShortNames: []string{"K. Mustermann", "Mustermann", "Mustermann"},
+			},
+			salutation: "Guten Tag Kim Mustermann,",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.individual.Recipient())
+			assert.Equal(t, tt.salutation, tt.individual.LetterSalutation())
+		})
+	}
+}
+
+func TestOrganization_Recipient(t *testing.T) {
+	organization := &Organization{
+		Name:        "Muster Handelsgesellschaft mbH",
+		TradingName: "Muster",
+		ContactPersons: []ContactPerson{
+			{Name: "Max", LastName: "Mustermann"},
+			{Title: "Dr.", Name: "Erika", LastName: "Mustermann", Preferred: true},
+		},
+	}
+
+	assert.Equal(t, Recipient{
+		Name:       "Muster Handelsgesellschaft mbH",
+		ShortNames: []string{"Muster"},
+		Attention:  "z. Hd. Dr. Erika Mustermann",
+	}, organization.Recipient())
+}
@@ -1,19 +1,24 @@
 package tripica
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	gohttp "net/http"
+	"net/url"
+	"strings"
 	"tripica-client/http"
 	"tripica-client/http/errors"
 	"tripica-client/log"
 )
 
 const (
-	billingBasePath = "/api/private/v1/agent/billing"
+	billingBasePath = "/api/private/{version}/agent/billing"
+
+	// billingQueryTransactionIDs is the legacy filter of applied billing charges, which isn't JSON encoded.
+	billingQueryTransactionIDs = "?" + filterQueryParam + "=transactionIds="
 
 	billingPathGetDueBillingAccountBalancesByCustomer = "/billingAccountBalance/customerOuid/%s/status/DUE"
-	billingPathGetAppliedBillingCharges               = "/appliedBillingCharge?filters=transactionIds="
+	billingPathGetAppliedBillingCharges               = "/appliedBillingCharge"
 	billingPathGetListOfSettlementNodeAdviceByAccount = "/settlement/billingAccountOuid/%s"
 	billingPathGetBillingAccountByMBA                 = "/billingAccount/name/%s"
 	billingPathGetBillingAccountsByCustomer           = "/billingAccount/customerOuid/%s"
@@ -23,13 +28,15 @@ const (
 type billingAPI struct {
 	httpClient *http.Client
 	address    string
+	endpoints  *endpointRegistry
+	decoder    *decoder
 
 	logger log.Logger
 }
 
 // GetBillingAccountByMBA retrieves a billing account using provided MBA.
 func (b *billingAPI) GetBillingAccountByMBA(mba string) (*BillingAccount, error) {
-	url := fmt.Sprintf(b.address+billingPathGetBillingAccountByMBA, mba)
+	url := fmt.Sprintf(b.address+b.endpoints.path(EndpointBillingAccountByMBA), mba)
 
 	resp, err := b.httpClient.Get(url)
 	if err != nil {
@@ -50,7 +57,9 @@ func (b *billingAPI) GetBillingAccountByMBA(mba string) (*BillingAccount, error)
 	}
 
 	var billingAccount *BillingAccount
-	if err := json.Unmarshal(resp.Body(), &billingAccount); err != nil {
+	if err := b.decoder.decode(
+		b.endpoints.path(EndpointBillingAccountByMBA), resp.Body(), &billingAccount,
+	); err != nil {
 		return nil, NewTriPicaError(errors.NewParseError(err, resp.Body()))
 	}
 
@@ -59,9 +68,16 @@ func (b *billingAPI) GetBillingAccountByMBA(mba string) (*BillingAccount, error)
 
 // GetDueBillingAccountBalancesByCustomer retrieves account balances for the customer that are due.
 func (b *billingAPI) GetDueBillingAccountBalancesByCustomer(customerOUID string) ([]*BillingAccountBalance, error) {
-	url := fmt.Sprintf(b.address+billingPathGetDueBillingAccountBalancesByCustomer, customerOUID)
+	return b.getDueBillingAccountBalancesByCustomer(context.Background(), customerOUID)
+}
 
-	resp, err := b.httpClient.Get(url)
+func (b *billingAPI) getDueBillingAccountBalancesByCustomer(
+	ctx context.Context,
+	customerOUID string,
+) ([]*BillingAccountBalance, error) {
+	url := fmt.Sprintf(b.address+b.endpoints.path(EndpointDueBillingAccountBalancesByCustomer), customerOUID)
+
+	resp, err := b.httpClient.Get(url, http.WithContext(ctx))
 	if err != nil {
 		return nil, NewTriPicaError(errors.NewHTTPRequestError(err))
 	}
@@ -78,7 +94,9 @@ func (b *billingAPI) GetDueBillingAccountBalancesByCustomer(customerOUID string)
 	}
 
 	var accountBalances []*BillingAccountBalance
-	if err := json.Unmarshal(resp.Body(), &accountBalances); err != nil {
+	if err := b.decoder.decode(
+		b.endpoints.path(EndpointDueBillingAccountBalancesByCustomer), resp.Body(), &accountBalances,
+	); err != nil {
 		return nil, NewTriPicaError(errors.NewParseError(err, resp.Body()))
 	}
 
@@ -86,8 +104,14 @@ func (b *billingAPI) GetDueBillingAccountBalancesByCustomer(customerOUID string)
 }
 
 // GetAppliedBillingChargesByTransactionIDs retrieves applied billing charges related to the transaction IDs.
+// Multiple transaction IDs are separated by commas.
 func (b *billingAPI) GetAppliedBillingChargesByTransactionIDs(transactionIDs string) ([]*AppliedBillingCharge, error) {
-	url := b.address + billingPathGetAppliedBillingCharges + transactionIDs
+	query, err := transactionIDsQuery(transactionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	url := b.address + b.endpoints.path(EndpointAppliedBillingCharges) + query
 
 	resp, err := b.httpClient.Get(url)
 	if err != nil {
@@ -106,19 +130,34 @@ func (b *billingAPI) GetAppliedBillingChargesByTransactionIDs(transactionIDs str
 	}
 
 	var charges []*AppliedBillingCharge
-	if err := json.Unmarshal(resp.Body(), &charges); err != nil {
+	if err := b.decoder.decode(b.endpoints.path(EndpointAppliedBillingCharges), resp.Body(), &charges); err != nil {
 		return nil, NewTriPicaError(errors.NewParseError(err, resp.Body()))
 	}
 
 	return charges, nil
 }
 
+// transactionIDsQuery builds the query of the applied billing charges endpoint, which predates JSON filters, and
+// expects the comma separated transaction IDs as they are. Each ID is query escaped, and empty IDs are rejected.
+func transactionIDsQuery(transactionIDs string) (string, error) {
+	ids := strings.Split(transactionIDs, ",")
+	for i, id := range ids {
+		if strings.TrimSpace(id) == "" {
+			return "", NewTriPicaError(fmt.Errorf("invalid filter: %w: transactionIds", errFilterEmptyValue))
+		}
+
+		ids[i] = url.QueryEscape(strings.TrimSpace(id))
+	}
+
+	return billingQueryTransactionIDs + strings.Join(ids, ","), nil
+}
+
 // GetSettlementNoteAdviceByBillingAccount retrieves settlement note advices for the billing account.
 func (b *billingAPI) GetSettlementNoteAdviceByBillingAccount(billingAccountOUID string) (
 	[]*SettlementNoteAdvice,
 	error,
 ) {
-	url := fmt.Sprintf(b.address+billingPathGetListOfSettlementNodeAdviceByAccount, billingAccountOUID)
+	url := fmt.Sprintf(b.address+b.endpoints.path(EndpointSettlementNoteAdvicesByBillingAccount), billingAccountOUID)
 
 	resp, err := b.httpClient.Get(url)
 	if err != nil {
@@ -137,7 +176,9 @@ func (b *billingAPI) GetSettlementNoteAdviceByBillingAccount(billingAccountOUID
 	}
 
 	var advices []*SettlementNoteAdvice
-	if err := json.Unmarshal(resp.Body(), &advices); err != nil {
+	if err := b.decoder.decode(
+		b.endpoints.path(EndpointSettlementNoteAdvicesByBillingAccount), resp.Body(), &advices,
+	); err != nil {
 		return nil, NewTriPicaError(errors.NewParseError(err, resp.Body()))
 	}
 
@@ -146,9 +187,13 @@ func (b *billingAPI) GetSettlementNoteAdviceByBillingAccount(billingAccountOUID
 
 // GetCustomerBillingAccounts returns the list of relevant MBAs and CBAs.
 func (b *billingAPI) GetCustomerBillingAccounts(customerOUID string) ([]*BillingAccount, error) {
-	url := fmt.Sprintf(b.address+billingPathGetBillingAccountsByCustomer, customerOUID)
+	return b.getCustomerBillingAccounts(context.Background(), customerOUID)
+}
 
-	resp, err := b.httpClient.Get(url)
+func (b *billingAPI) getCustomerBillingAccounts(ctx context.Context, customerOUID string) ([]*BillingAccount, error) {
+	url := fmt.Sprintf(b.address+b.endpoints.path(EndpointBillingAccountsByCustomer), customerOUID)
+
+	resp, err := b.httpClient.Get(url, http.WithContext(ctx))
 	if err != nil {
 		return nil, NewTriPicaError(errors.NewHTTPRequestError(err))
 	}
@@ -165,13 +210,138 @@ func (b *billingAPI) GetCustomerBillingAccounts(customerOUID string) ([]*Billing
 	}
 
 	var billingAccounts []*BillingAccount
-	if err := json.Unmarshal(resp.Body(), &billingAccounts); err != nil {
+	if err := b.decoder.decode(
+		b.endpoints.path(EndpointBillingAccountsByCustomer), resp.Body(), &billingAccounts,
+	); err != nil {
 		return nil, NewTriPicaError(errors.NewParseError(err, resp.Body()))
 	}
 
 	return billingAccounts, nil
 }
 
+// IterateCustomerBillingAccounts lazily iterates over the customer's billing accounts matching the optional filter,
+// fetching pageSize accounts at once.
+func (b *billingAPI) IterateCustomerBillingAccounts(
+	ctx context.Context,
+	customerOUID string,
+	filter *Filter,
+	pageSize int,
+) *Iterator[*BillingAccount] {
+	url, err := b.filteredURL(EndpointBillingAccountsByCustomer, customerOUID, filter)
+
+	return newIterator(ctx, pageSize, func(ctx context.Context, offset, limit int) ([]*BillingAccount, error) {
+		if err != nil {
+			return nil, err
+		}
+
+		return getPage[*BillingAccount](
+			ctx, b.httpClient, b.decoder, url, b.endpoints.path(EndpointBillingAccountsByCustomer), offset, limit,
+			"customer billing accounts with customerOUID "+customerOUID,
+		)
+	})
+}
+
+// IterateDueBillingAccountBalancesByCustomer lazily iterates over the customer's due account balances
+// matching the optional filter, fetching pageSize balances at once.
+func (b *billingAPI) IterateDueBillingAccountBalancesByCustomer(
+	ctx context.Context,
+	customerOUID string,
+	filter *Filter,
+	pageSize int,
+) *Iterator[*BillingAccountBalance] {
+	url, err := b.filteredURL(EndpointDueBillingAccountBalancesByCustomer, customerOUID, filter)
+
+	return newIterator(ctx, pageSize, func(ctx context.Context, offset, limit int) ([]*BillingAccountBalance, error) {
+		if err != nil {
+			return nil, err
+		}
+
+		return getPage[*BillingAccountBalance](
+			ctx, b.httpClient, b.decoder, url, b.endpoints.path(EndpointDueBillingAccountBalancesByCustomer),
+			offset, limit, "billing balances with customerOUID "+customerOUID,
+		)
+	})
+}
+
+// IterateAppliedBillingCharges lazily iterates over the applied billing charges matching the filter,
+// fetching pageSize charges at once.
+//
+//	it := client.IterateAppliedBillingCharges(ctx, NewFilter().In("transactionIds", ids...), 0)
+func (b *billingAPI) IterateAppliedBillingCharges(
+	ctx context.Context,
+	filter *Filter,
+	pageSize int,
+) *Iterator[*AppliedBillingCharge] {
+	url, err := withFilter(b.address+b.endpoints.path(EndpointAppliedBillingCharges), filter)
+
+	return newIterator(ctx, pageSize, func(ctx context.Context, offset, limit int) ([]*AppliedBillingCharge, error) {
+		if err != nil {
+			return nil, err
+		}
+
+		return getPage[*AppliedBillingCharge](
+			ctx, b.httpClient, b.decoder, url, b.endpoints.path(EndpointAppliedBillingCharges), offset, limit,
+			"billing charges",
+		)
+	})
+}
+
+// StreamDueBillingAccountBalancesByCustomer streams the customer's due account balances matching the optional filter,
+// calling fn for each balance as soon as it is decoded.
+func (b *billingAPI) StreamDueBillingAccountBalancesByCustomer(
+	ctx context.Context,
+	customerOUID string,
+	filter *Filter,
+	fn func(*BillingAccountBalance) error,
+) error {
+	url, err := b.filteredURL(EndpointDueBillingAccountBalancesByCustomer, customerOUID, filter)
+	if err != nil {
+		return err
+	}
+
+	return streamArray(
+		ctx, b.httpClient, b.decoder, url, b.endpoints.path(EndpointDueBillingAccountBalancesByCustomer),
+		"billing balances with customerOUID "+customerOUID, fn,
+	)
+}
+
+// StreamAppliedBillingCharges streams the applied billing charges matching the filter,
+// calling fn for each charge as soon as it is decoded.
+func (b *billingAPI) StreamAppliedBillingCharges(
+	ctx context.Context,
+	filter *Filter,
+	fn func(*AppliedBillingCharge) error,
+) error {
+	url, err := withFilter(b.address+b.endpoints.path(EndpointAppliedBillingCharges), filter)
+	if err != nil {
+		return err
+	}
+
+	return streamArray(
+		ctx, b.httpClient, b.decoder, url, b.endpoints.path(EndpointAppliedBillingCharges), "billing charges", fn,
+	)
+}
+
+// StreamSettlementNoteAdviceByBillingAccount streams the settlement note advices for the billing account,
+// calling fn for each advice as soon as it is decoded.
+func (b *billingAPI) StreamSettlementNoteAdviceByBillingAccount(
+	ctx context.Context,
+	billingAccountOUID string,
+	fn func(*SettlementNoteAdvice) error,
+) error {
+	url := fmt.Sprintf(b.address+b.endpoints.path(EndpointSettlementNoteAdvicesByBillingAccount), billingAccountOUID)
+
+	return streamArray(
+		ctx, b.httpClient, b.decoder, url, b.endpoints.path(EndpointSettlementNoteAdvicesByBillingAccount),
+		"settlement notes with billingAccountOUID "+billingAccountOUID, fn,
+	)
+}
+
+// filteredURL builds the URL of a customer's billing endpoint, with the optional filter applied.
+func (b *billingAPI) filteredURL(endpoint Endpoint, customerOUID string, filter *Filter) (string, error) {
+	return withFilter(fmt.Sprintf(b.address+b.endpoints.path(endpoint), customerOUID), filter)
+}
+
 const (
 	billPresentationMediaPostmail = "POSTMAIL"
 )
@@ -179,19 +349,21 @@ const (
 // Models for exchanging data with tripica.
 type (
 	BillingAccount struct {
-		OUID                        string                       `json:"ouid"`
-		Name                        string                       `json:"name"`
-		CustomerOUID                string                       `json:"customerOuid"`
+		OUID                        string                       `json:"ouid" tripica:"required"`
+		Name                        string                       `json:"name" tripica:"required"`
+		CustomerOUID                string                       `json:"customerOuid" tripica:"required"`
 		BillPresentationMedia       string                       `json:"billPresentationMedia"`
 		DateTimeCreate              Date                         `json:"dateTimeCreate"`
 		BillingAccountRelationships []BillingAccountRelationship `json:"billingAccountRelationships"`
+		Extensions                  Extensions                   `json:"-"`
 	}
 
 	// BillingAccountRelationship represents a triPica billing account relationship.
 	BillingAccountRelationship struct {
-		OUID                     string `json:"ouid"`
-		Type                     string `json:"type"`
-		TargetBillingAccountOUID string `json:"targetBillingAccountOuid"`
+		OUID                     string     `json:"ouid"`
+		Type                     string     `json:"type"`
+		TargetBillingAccountOUID string     `json:"targetBillingAccountOuid"`
+		Extensions               Extensions `json:"-"`
 	}
 )
 
@@ -214,31 +386,120 @@ const (
 
 // BillingAccountBalance represents a triPica billing account balance.
 type BillingAccountBalance struct {
-	OUID                     string `json:"ouid"`
-	BillingAccountOUID       string `json:"billingAccountOuid"`
-	Amount                   int    `json:"amount"`
-	Status                   string `json:"status"`
-	Type                     string `json:"type"`
-	TransactionID            string `json:"transactionId"`
-	SettlementNoteAdviceOUID string `json:"settlementNoteAdviceOuid"`
-	StartDate                Date   `json:"startDateTime"`
+	OUID                     string     `json:"ouid" tripica:"required"`
+	BillingAccountOUID       string     `json:"billingAccountOuid" tripica:"required"`
+	Amount                   Money      `json:"amount" tripica:"required"`
+	CurrencyCode             string     `json:"currencyCode"`
+	Status                   string     `json:"status" tripica:"required"`
+	Type                     string     `json:"type"`
+	TransactionID            string     `json:"transactionId"`
+	SettlementNoteAdviceOUID string     `json:"settlementNoteAdviceOuid"`
+	StartDate                Date       `json:"startDateTime"`
+	Extensions               Extensions `json:"-"`
 }
 
 // AppliedBillingCharge represents a triPica applied billing charge.
 type AppliedBillingCharge struct {
-	OUID               string `json:"ouid"`
-	GeneralLedgerID    string `json:"glid"`
-	BillingAccountOUID string `json:"billingAccountOuid"`
-	TransactionID      string `json:"transactionId"`
-	CurrencyCode       string `json:"currencyCode"`
+	OUID               string     `json:"ouid" tripica:"required"`
+	GeneralLedgerID    string     `json:"glid"`
+	BillingAccountOUID string     `json:"billingAccountOuid"`
+	TransactionID      string     `json:"transactionId" tripica:"required"`
+	Amount             Money      `json:"amount"`
+	CurrencyCode       string     `json:"currencyCode"`
+	Extensions         Extensions `json:"-"`
 }
 
 // SettlementNoteAdvice represents a triPica settlement note advice.
 type SettlementNoteAdvice struct {
-	OUID           string `json:"ouid"`
-	ID             string `json:"id"`
-	BillDate       Date   `json:"billDate"`
-	PaymentDueDate int64  `json:"paymentDueDate"`
-	Category       string `json:"category"`
-	State          string `json:"state"`
+	OUID           string     `json:"ouid" tripica:"required"`
+	ID             string     `json:"id" tripica:"required"`
+	BillDate       Date       `json:"billDate"`
+	PaymentDueDate Date       `json:"paymentDueDate"`
+	Category       string     `json:"category"`
+	State          string     `json:"state"`
+	Extensions     Extensions `json:"-"`
+}
+
+// UnmarshalJSON decodes the BillingAccount, retaining undeclared properties in its Extensions.
+func (a *BillingAccount) UnmarshalJSON(data []byte) error {
+	type billingAccount BillingAccount
+
+	return unmarshalExtended(data, (*billingAccount)(a), &a.Extensions)
+}
+
+// MarshalJSON encodes the BillingAccount, including its Extensions.
+func (a BillingAccount) MarshalJSON() ([]byte, error) {
+	type billingAccount BillingAccount
+
+	return marshalExtended(billingAccount(a), a.Extensions)
+}
+
+// UnmarshalJSON decodes the BillingAccountRelationship, retaining undeclared properties in its Extensions.
+func (r *BillingAccountRelationship) UnmarshalJSON(data []byte) error {
+	type billingAccountRelationship BillingAccountRelationship
+
+	return unmarshalExtended(data, (*billingAccountRelationship)(r), &r.Extensions)
+}
+
+// MarshalJSON encodes the BillingAccountRelationship, including its Extensions.
+func (r BillingAccountRelationship) MarshalJSON() ([]byte, error) {
+	type billingAccountRelationship BillingAccountRelationship
+
+	return marshalExtended(billingAccountRelationship(r), r.Extensions)
+}
+
+// UnmarshalJSON decodes the BillingAccountBalance, retaining undeclared properties in its Extensions.
+// The Amount is in the balance's CurrencyCode, or in EUR if triPica doesn't return one.
+func (b *BillingAccountBalance) UnmarshalJSON(data []byte) error {
+	type billingAccountBalance BillingAccountBalance
+
+	if err := unmarshalExtended(data, (*billingAccountBalance)(b), &b.Extensions); err != nil {
+		return err
+	}
+
+	b.Amount = b.Amount.WithCurrency(Currency(b.CurrencyCode))
+
+	return nil
+}
+
+// MarshalJSON encodes the BillingAccountBalance, including its Extensions.
+func (b BillingAccountBalance) MarshalJSON() ([]byte, error) {
+	type billingAccountBalance BillingAccountBalance
+
+	return marshalExtended(billingAccountBalance(b), b.Extensions)
+}
+
+// UnmarshalJSON decodes the AppliedBillingCharge, retaining undeclared properties in its Extensions.
+// The Amount is in the charge's CurrencyCode.
+func (c *AppliedBillingCharge) UnmarshalJSON(data []byte) error {
+	type appliedBillingCharge AppliedBillingCharge
+
+	if err := unmarshalExtended(data, (*appliedBillingCharge)(c), &c.Extensions); err != nil {
+		return err
+	}
+
+	c.Amount = c.Amount.WithCurrency(Currency(c.CurrencyCode))
+
+	return nil
+}
+
+// MarshalJSON encodes the AppliedBillingCharge, including its Extensions.
+func (c AppliedBillingCharge) MarshalJSON() ([]byte, error) {
+	type appliedBillingCharge AppliedBillingCharge
+
+	return marshalExtended(appliedBillingCharge(c), c.Extensions)
+}
+
+// UnmarshalJSON decodes the SettlementNoteAdvice, retaining undeclared properties in its Extensions.
+func (a *SettlementNoteAdvice) UnmarshalJSON(data []byte) error {
+	type settlementNoteAdvice SettlementNoteAdvice
+
+	return unmarshalExtended(data, (*settlementNoteAdvice)(a), &a.Extensions)
+}
+
+// MarshalJSON encodes the SettlementNoteAdvice, including its Extensions.
+func (a SettlementNoteAdvice) MarshalJSON() ([]byte, error) {
+	type settlementNoteAdvice SettlementNoteAdvice
+
+	return marshalExtended(settlementNoteAdvice(a), a.Extensions)
 }
@@ -0,0 +1,59 @@
+package tripica
+
+import (
+	gohttp "net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAppliedBillingChargesByTransactionIDs(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var path, query string
+
+	client := newTestClient(t, gohttp.HandlerFunc(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		path, query = r.URL.Path, r.URL.RawQuery
+		_, _ = w.Write([]byte(`[{"ouid":"1"}]`))
+	}), nil)
+
+	charges, err := client.GetAppliedBillingChargesByTransactionIDs("T1,T2")
+	require.NoError(err)
+	assert.Len(charges, 1)
+	assert.Equal("/api/private/v1/agent/billing/appliedBillingCharge", path)
+	assert.Equal("filters=transactionIds=T1,T2", query)
+}
+
+func TestTransactionIDsQuery(t *testing.T) {
+	tests := []struct {
+		name           string
+		transactionIDs string
+		want           string
+		wantErr        bool
+	}{
+		{name: "single", transactionIDs: "T1", want: "?filters=transactionIds=T1"},
+		{name: "multiple", transactionIDs: "T1, T2", want: "?filters=transactionIds=T1,T2"},
+		{name: "escaped", transactionIDs: "T1&status=X,T#2", want: "?filters=transactionIds=T1%26status%3DX,T%232"},
+		{name: "empty", transactionIDs: "", wantErr: true},
+		{name: "empty ID", transactionIDs: "T1,,T2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := transactionIDsQuery(tt.transactionIDs)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Empty(t, query)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, query)
+		})
+	}
+}
@@ -3,6 +3,8 @@ package tripica
 import (
 	"fmt"
 	"sync"
+	"time"
+	"tripica-client/clock"
 	"tripica-client/http"
 	"tripica-client/http/errors"
 	"tripica-client/jwt"
@@ -18,6 +20,7 @@ type Client struct {
 	mux         sync.Mutex
 	httpClient  *http.Client
 	logger      log.Logger
+	clock       clock.Clock
 
 	*loginAPI
 	*billingAPI
@@ -26,12 +29,19 @@ type Client struct {
 	*productAPI
 	*networkEntityAPI
 	*notifyAPI
+	*organizationAPI
 }
 
 // Config configures the required information for accessing triPica endpoints.
+// StrictMode is optional, and enables the detection of schema drift when decoding responses.
+// Clock is optional as well, and defaults to the system clock.
+// Endpoints optionally overrides the paths and API versions of the endpoints.
 type Config struct {
 	Host        string
 	Credentials Credentials
+	StrictMode  *StrictMode
+	Clock       clock.Clock
+	Endpoints   *Endpoints
 }
 
 // Credentials objects hold data allowing the service to be authenticated by triPica.
@@ -43,64 +53,114 @@ type Credentials struct {
 	Alias    string
 }
 
+// Validate checks the configuration, so that invalid endpoint overrides are detected at startup.
+func (c Config) Validate() error {
+	return c.Endpoints.Validate()
+}
+
+// New returns Client for communication to tripica, or an error if the configuration is invalid.
+func New(config Config, client *http.Client, logger log.Logger) (*Client, error) {
+	endpoints, err := newEndpointRegistry(config.Endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClient(config, endpoints, client, logger), nil
+}
+
 // NewClient returns Client for communication to tripica.
+// If the configured endpoints are invalid, the error is logged and the default endpoints are used instead;
+// New allows handling the error.
 func NewClient(config Config, client *http.Client, logger log.Logger) *Client {
+	endpoints, err := newEndpointRegistry(config.Endpoints)
+	if err != nil {
+		if logger != nil {
+			logger.WithFields(map[string]interface{}{
+				"error": err.Error(),
+			}).Error("invalid triPica endpoints, falling back to the default endpoints")
+		}
+
+		endpoints, _ = newEndpointRegistry(nil)
+	}
+
+	return newClient(config, endpoints, client, logger)
+}
+
+func newClient(config Config, endpoints *endpointRegistry, client *http.Client, logger log.Logger) *Client {
 	c := &Client{
 		address:     config.Host,
 		credentials: config.Credentials,
 		logger:      logger,
-	}
-
-	tokenHolder := &Client{
-		credentials: config.Credentials,
+		clock:       clock.OrSystem(config.Clock),
 	}
 
 	client.Apply(
-		http.WithAuthToken(tokenHolder),
+		http.WithAuthToken(c),
+		http.WithClock(c.clock),
 	)
 
 	c.httpClient = client
+	decoder := newDecoder(config.StrictMode, logger)
 
 	c.loginAPI = &loginAPI{
-		httpClient:      client,
-		address:         c.address,
-		addressAgent:    c.address + loginBasePathAgent,
-		addressCustomer: c.address + loginBasePathCustomer,
-		logger:          logger,
+		httpClient: client,
+		address:    c.address,
+		endpoints:  endpoints,
+		decoder:    decoder,
+		logger:     logger,
 	}
 
 	c.billingAPI = &billingAPI{
 		httpClient: client,
-		address:    c.address + billingBasePath,
+		address:    c.address,
+		endpoints:  endpoints,
+		decoder:    decoder,
 		logger:     logger,
 	}
 
 	c.customerAPI = &customerAPI{
 		httpClient: client,
-		address:    c.address + customerBasePath,
+		address:    c.address,
+		endpoints:  endpoints,
+		decoder:    decoder,
 	}
 
 	c.individualAPI = &individualAPI{
 		httpClient: client,
-		address:    c.address + individualBasePath,
+		address:    c.address,
+		endpoints:  endpoints,
+		decoder:    decoder,
 		logger:     logger,
 	}
 
 	c.networkEntityAPI = &networkEntityAPI{
 		httpClient: client,
-		address:    c.address + networkEntityBasePath,
+		address:    c.address,
+		endpoints:  endpoints,
+		decoder:    decoder,
 		logger:     logger,
 	}
 
 	c.productAPI = &productAPI{
 		httpClient: client,
-		address:    c.address + productBasePath,
+		address:    c.address,
+		endpoints:  endpoints,
+		decoder:    decoder,
 		logger:     logger,
 	}
 
 	c.notifyAPI = &notifyAPI{
 		httpClient: client,
 		address:    c.address,
+		endpoints:  endpoints,
+	}
+
+	c.organizationAPI = &organizationAPI{
+		httpClient: client,
+		address:    c.address,
+		endpoints:  endpoints,
+		decoder:    decoder,
+		logger:     logger,
 	}
 
 	return c
@@ -118,7 +178,7 @@ func (c *Client) RefreshToken() error {
 	c.mux.Lock()
 	defer c.mux.Unlock()
 
-	if c.token != nil && !c.token.IsExpired() {
+	if c.token != nil && !c.token.IsExpiredAt(c.clock.Now()) {
 		return nil
 	}
 
@@ -127,6 +187,10 @@ func (c *Client) RefreshToken() error {
 	token, err := c.loginAPI.authorize(c.credentials)
 	if err != nil {
 		authErr := &errors.AuthorizationError{Err: err}
+		c.logger.WithFields(map[string]interface{}{
+			"error": authErr.Error(),
+		}).Warn("couldn't refresh triPica token")
+
 		return fmt.Errorf("couldn't authorize with triPica: %s", authErr)
 	}
 
@@ -135,6 +199,12 @@ func (c *Client) RefreshToken() error {
 	return nil
 }
 
+// Now returns the current time of the client's clock. It should be used when checking
+// time dependent properties of models, e.g. Individual.DeliveryAddress(client.Now()).
+func (c *Client) Now() time.Time {
+	return c.clock.Now()
+}
+
 // RawToken returns the raw underlying token.
 func (c *Client) RawToken() string {
 	return c.token.Raw
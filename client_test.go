@@ -0,0 +1,69 @@
+package tripica
+
+import (
+	gohttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"tripica-client/http"
+	"tripica-client/log"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testToken is a JWT expiring in 2286, accepted by the client without verifying its signature.
+const testToken = "eyJhbGciOiJIUzI1NiJ9.eyJleHAiOjk5OTk5OTk5OTl9.c2ln"
+
+// newTestClient returns a Client talking to a test server, which authorizes every login and passes all other
+// requests to the handler.
+func newTestClient(t *testing.T, handler gohttp.Handler, logger log.Logger) *Client {
+	t.Helper()
+
+	mux := gohttp.NewServeMux()
+	mux.HandleFunc("/api/v1/login/jwt", func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		w.WriteHeader(gohttp.StatusCreated)
+		_, _ = w.Write([]byte(`{"token":"` + testToken + `"}`))
+	})
+	mux.Handle("/", handler)
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	if logger == nil {
+		logger = log.NewTestLogger()
+	}
+
+	return NewClient(Config{Host: srv.URL}, http.NewClient(logger), logger)
+}
+
+func TestNew(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("invalid endpoints are rejected", func(t *testing.T) {
+		client, err := New(Config{Endpoints: &Endpoints{Versions: map[APIArea]string{AreaBilling: "v/2"}}},
+			http.NewClient(nil), nil)
+
+		assert.Error(err)
+		assert.Nil(client)
+	})
+
+	t.Run("endpoints are configured", func(t *testing.T) {
+		client, err := New(Config{Endpoints: &Endpoints{Versions: map[APIArea]string{AreaBilling: "v2"}}},
+			http.NewClient(nil), nil)
+
+		assert.NoError(err)
+		assert.Equal("/api/private/v2/agent/billing/appliedBillingCharge",
+			client.billingAPI.endpoints.path(EndpointAppliedBillingCharges))
+	})
+}
+
+func TestNewClient(t *testing.T) {
+	assert := assert.New(t)
+
+	logger := log.NewCapturingLogger()
+	client := NewClient(Config{Endpoints: &Endpoints{Versions: map[APIArea]string{AreaBilling: "v/2"}}},
+		http.NewClient(logger), logger)
+
+	assert.Equal("/api/private/v1/agent/billing/appliedBillingCharge",
+		client.billingAPI.endpoints.path(EndpointAppliedBillingCharges))
+	assert.Len(logger.EntriesWithLevel(log.LevelError), 1)
+}
@@ -0,0 +1,139 @@
+// Package clock abstracts time, so that time dependent behaviour such as token expiry,
+// validity periods and retry backoff can be controlled in tests.
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+type (
+	// Clock provides the current time and timers.
+	Clock interface {
+		Now() time.Time
+		After(d time.Duration) <-chan time.Time
+	}
+
+	// systemClock is the Clock backed by the time package.
+	systemClock struct{}
+
+	// Fake is a Clock which only moves when told to. It is safe for concurrent use.
+	Fake struct {
+		now     time.Time
+		waiters []*waiter
+		mux     sync.Mutex
+		cond    *sync.Cond
+	}
+
+	waiter struct {
+		deadline time.Time
+		ch       chan time.Time
+	}
+)
+
+// System returns the Clock backed by the time package.
+func System() Clock {
+	return systemClock{}
+}
+
+// OrSystem returns the provided clock, or the system clock if it is nil.
+func OrSystem(c Clock) Clock {
+	if c == nil {
+		return System()
+	}
+
+	return c
+}
+
+// Now returns the current local time.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After waits for the duration to elapse and then sends the current time on the returned channel.
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// NewFake returns a Fake clock set to the provided time.
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.cond = sync.NewCond(&f.mux)
+
+	return f
+}
+
+// Now returns the current time of the fake clock.
+func (f *Fake) Now() time.Time {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	return f.now
+}
+
+// After returns a channel which receives the fake time once the clock was advanced by at least d.
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- f.now
+
+		return ch
+	}
+
+	f.waiters = append(f.waiters, &waiter{deadline: f.now.Add(d), ch: ch})
+	f.cond.Broadcast()
+
+	return ch
+}
+
+// Advance moves the clock forward, firing all timers whose deadline has passed.
+func (f *Fake) Advance(d time.Duration) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	f.set(f.now.Add(d))
+}
+
+// Set moves the clock to the provided time, firing all timers whose deadline has passed.
+func (f *Fake) Set(now time.Time) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	f.set(now)
+}
+
+// BlockUntil blocks until at least n timers are waiting for the clock to advance.
+func (f *Fake) BlockUntil(n int) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	for len(f.waiters) < n {
+		f.cond.Wait()
+	}
+}
+
+func (f *Fake) set(now time.Time) {
+	f.now = now
+
+	sort.Slice(f.waiters, func(i, j int) bool {
+		return f.waiters[i].deadline.Before(f.waiters[j].deadline)
+	})
+
+	remaining := f.waiters[:0]
+
+	for _, w := range f.waiters {
+		if w.deadline.After(now) {
+			remaining = append(remaining, w)
+
+			continue
+		}
+
+		w.ch <- now
+	}
+
+	f.waiters = remaining
+}
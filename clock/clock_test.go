@@ -0,0 +1,62 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+	"tripica-client/clock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFake(t *testing.T) {
+	assert := assert.New(t)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("time only moves when advanced", func(t *testing.T) {
+		c := clock.NewFake(start)
+		assert.Equal(start, c.Now())
+
+		c.Advance(time.Hour)
+		assert.Equal(start.Add(time.Hour), c.Now())
+
+		c.Set(start)
+		assert.Equal(start, c.Now())
+	})
+
+	t.Run("timers fire once their deadline passes", func(t *testing.T) {
+		c := clock.NewFake(start)
+		ch := c.After(time.Minute)
+
+		c.Advance(30 * time.Second)
+		select {
+		case <-ch:
+			t.Fatal("timer fired too early")
+		default:
+		}
+
+		c.Advance(30 * time.Second)
+		assert.Equal(start.Add(time.Minute), <-ch)
+	})
+
+	t.Run("BlockUntil waits for timers", func(t *testing.T) {
+		c := clock.NewFake(start)
+		done := make(chan struct{})
+
+		go func() {
+			<-c.After(time.Second)
+			close(done)
+		}()
+
+		c.BlockUntil(1)
+		c.Advance(time.Second)
+		<-done
+	})
+}
+
+func TestOrSystem(t *testing.T) {
+	assert := assert.New(t)
+
+	fake := clock.NewFake(time.Time{})
+	assert.Equal(fake, clock.OrSystem(fake))
+	assert.Equal(clock.System(), clock.OrSystem(nil))
+}
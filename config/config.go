@@ -0,0 +1,422 @@
+// Package config loads the configuration of a triPica client from files and environment variables.
+//
+// Sources are applied to the defaults in the order they are provided, later sources overriding earlier ones.
+// Passing FromEnv last therefore lets environment variables override the files:
+//
+//	cfg, err := config.Load(config.FromFile("/etc/tripica.yaml"), config.FromEnv("TRIPICA_"))
+//	if err != nil {
+//		return err
+//	}
+//
+//	client := cfg.NewClient(logger)
+package config
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"tripica-client"
+	"tripica-client/http"
+	"tripica-client/log"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// Possible log formats.
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// Default values of settings which aren't configured.
+const (
+	DefaultMaxRetries  = 4
+	DefaultWaitTime    = Duration(1000 * time.Millisecond)
+	DefaultMaxWaitTime = Duration(2000 * time.Millisecond)
+	DefaultTimeout     = Duration(5000 * time.Millisecond)
+)
+
+type (
+	// Config holds the settings of a triPica client. Configs which aren't returned by Load need to be resolved
+	// using Resolve before they are used, as the password file and the TLS settings are ignored otherwise.
+	Config struct {
+		Host        string            `json:"host" yaml:"host"`
+		Credentials CredentialsConfig `json:"credentials" yaml:"credentials"`
+		Retry       RetryConfig       `json:"retry" yaml:"retry"`
+		Timeout     Duration          `json:"timeout" yaml:"timeout"`
+		TLS         TLSConfig         `json:"tls" yaml:"tls"`
+		Log         LogConfig         `json:"log" yaml:"log"`
+
+		// tls is the TLS configuration built from the TLS settings by Resolve.
+		tls *tls.Config
+	}
+
+	// CredentialsConfig holds the triPica credentials. Instead of an inline password,
+	// PasswordFile can reference a file containing it, e.g. a mounted secret.
+	CredentialsConfig struct {
+		Email        string `json:"email" yaml:"email"`
+		Alias        string `json:"alias" yaml:"alias"`
+		Password     string `json:"password" yaml:"password"`
+		PasswordFile string `json:"passwordFile" yaml:"passwordFile"`
+	}
+
+	// RetryConfig holds the settings of retried requests. Zero MaxRetries disables retries.
+	RetryConfig struct {
+		MaxRetries  uint     `json:"maxRetries" yaml:"maxRetries"`
+		WaitTime    Duration `json:"waitTime" yaml:"waitTime"`
+		MaxWaitTime Duration `json:"maxWaitTime" yaml:"maxWaitTime"`
+	}
+
+	// TLSConfig holds the TLS settings. CAFile adds root CAs to the system ones, while CertFile
+	// and KeyFile configure a client certificate, and need to be provided together.
+	TLSConfig struct {
+		CAFile             string `json:"caFile" yaml:"caFile"`
+		CertFile           string `json:"certFile" yaml:"certFile"`
+		KeyFile            string `json:"keyFile" yaml:"keyFile"`
+		ServerName         string `json:"serverName" yaml:"serverName"`
+		InsecureSkipVerify bool   `json:"insecureSkipVerify" yaml:"insecureSkipVerify"`
+	}
+
+	// LogConfig holds the logging settings. Debug enables the request dumps of the HTTP client.
+	LogConfig struct {
+		Level  log.Level `json:"level" yaml:"level"`
+		Format string    `json:"format" yaml:"format"`
+		Debug  bool      `json:"debug" yaml:"debug"`
+	}
+
+	// Source applies the settings of a single source to the Config.
+	Source func(*Config) error
+
+	// ValidationError lists every problem of an invalid Config.
+	ValidationError struct {
+		Problems []string
+	}
+)
+
+// Error makes ValidationError implement the error interface.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid triPica config: %s", strings.Join(e.Problems, "; "))
+}
+
+// Default returns the Config holding the default settings.
+func Default() *Config {
+	return &Config{
+		Retry: RetryConfig{
+			MaxRetries:  DefaultMaxRetries,
+			WaitTime:    DefaultWaitTime,
+			MaxWaitTime: DefaultMaxWaitTime,
+		},
+		Timeout: DefaultTimeout,
+		Log: LogConfig{
+			Level:  log.LevelInfo,
+			Format: LogFormatText,
+		},
+	}
+}
+
+// Load applies the sources to the default settings in the order they are provided, later sources overriding
+// earlier ones, and resolves the result.
+func Load(sources ...Source) (*Config, error) {
+	c := Default()
+
+	for _, source := range sources {
+		if err := source(c); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.Resolve(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// FromFile reads the settings of a YAML or JSON file, depending on its extension.
+// Settings which the file doesn't contain are left unchanged.
+func FromFile(path string) Source {
+	return func(c *Config) error {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("couldn't read triPica config file: %w", err)
+		}
+
+		unmarshal := unmarshalYAML
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			unmarshal = unmarshalJSON
+		}
+
+		var layer Config
+		if err := unmarshal(data, &layer); err != nil {
+			return fmt.Errorf("couldn't parse triPica config file %s: %w", path, err)
+		}
+
+		if err := layer.Credentials.checkPassword(path); err != nil {
+			return err
+		}
+
+		if err := unmarshal(data, c); err != nil {
+			return fmt.Errorf("couldn't parse triPica config file %s: %w", path, err)
+		}
+
+		c.Credentials.overridePassword(layer.Credentials)
+
+		return nil
+	}
+}
+
+// Validate checks the settings, reporting all problems at once.
+func (c *Config) Validate() error {
+	var problems []string
+
+	if c.Host == "" {
+		problems = append(problems, "host is required")
+	} else if u, err := url.Parse(c.Host); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		problems = append(problems, fmt.Sprintf("host %q needs to be an absolute http or https URL", c.Host))
+	}
+
+	if c.Credentials.Password == "" && c.Credentials.PasswordFile == "" {
+		problems = append(problems, "credentials require a password or password file")
+	}
+
+	if c.Credentials.Email == "" && c.Credentials.Alias == "" {
+		problems = append(problems, "credentials require an email or alias")
+	}
+
+	if c.Retry.WaitTime < 0 || c.Retry.MaxWaitTime < 0 {
+		problems = append(problems, "retry wait times can't be negative")
+	} else if c.Retry.WaitTime > c.Retry.MaxWaitTime {
+		problems = append(problems, fmt.Sprintf(
+			"retry wait time %s exceeds the max wait time %s", c.Retry.WaitTime, c.Retry.MaxWaitTime,
+		))
+	}
+
+	if c.Timeout <= 0 {
+		problems = append(problems, fmt.Sprintf("timeout %s needs to be positive", c.Timeout))
+	}
+
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		problems = append(problems, "tls cert file and key file need to be provided together")
+	}
+
+	switch c.Log.Level {
+	case log.LevelDebug, log.LevelInfo, log.LevelWarn, log.LevelError:
+	default:
+		problems = append(problems, fmt.Sprintf("unknown log level %q", c.Log.Level))
+	}
+
+	if c.Log.Format != LogFormatText && c.Log.Format != LogFormatJSON {
+		problems = append(problems, fmt.Sprintf("unknown log format %q", c.Log.Format))
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+// TriPicaConfig returns the configuration of the triPica client.
+func (c *Config) TriPicaConfig() tripica.Config {
+	return tripica.Config{
+		Host: strings.TrimRight(c.Host, "/"),
+		Credentials: tripica.Credentials{
+			Email:    c.Credentials.Email,
+			Password: c.Credentials.Password,
+			Alias:    c.Credentials.Alias,
+		},
+	}
+}
+
+// RetryerConfig returns the configuration of retried requests.
+func (c *Config) RetryerConfig() *http.RetryerConfig {
+	return http.NewRetryerConfig(
+		c.Retry.MaxRetries,
+		uint(c.Retry.WaitTime.Milliseconds()),
+		uint(c.Retry.MaxWaitTime.Milliseconds()),
+		uint(c.Timeout.Milliseconds()),
+	)
+}
+
+// ClientOptions returns the options of the HTTP client.
+func (c *Config) ClientOptions() []http.ClientOption {
+	return []http.ClientOption{
+		http.ConfigureRetryer(c.RetryerConfig()),
+		http.WithTLSConfig(c.tls),
+		http.WithDebug(http.NewDebugConfig(c.Log.Debug, nil)),
+	}
+}
+
+// Logger returns a Logger writing to stderr, in the configured format and level.
+func (c *Config) Logger() log.Logger {
+	logger := logrus.New()
+	logger.SetOutput(os.Stderr)
+	logger.SetLevel(logrusLevel(c.Log.Level))
+
+	if c.Log.Format == LogFormatJSON {
+		logger.SetFormatter(&logrus.JSONFormatter{})
+	}
+
+	return log.NewLogrus(logger)
+}
+
+// NewClient returns a triPica client using the settings. If logger is nil, the configured Logger is used.
+func (c *Config) NewClient(logger log.Logger) *tripica.Client {
+	if logger == nil {
+		logger = c.Logger()
+	}
+
+	return tripica.NewClient(c.TriPicaConfig(), http.NewClient(logger, c.ClientOptions()...), logger)
+}
+
+// Resolve validates the settings, reads the password file and loads the TLS files.
+func (c *Config) Resolve() error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	var problems []string
+
+	if c.Credentials.PasswordFile != "" {
+		password, err := readSecret(c.Credentials.PasswordFile)
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+
+		c.Credentials.Password = password
+	}
+
+	tlsConfig, err := c.TLS.load()
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	c.tls = tlsConfig
+
+	return nil
+}
+
+// load builds the TLS configuration, returning nil if the default one is sufficient.
+func (t TLSConfig) load() (*tls.Config, error) {
+	if t == (TLSConfig{}) {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify, //nolint: gosec
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if t.CAFile != "" {
+		pem, err := ioutil.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't read tls ca file: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("tls ca file %s doesn't contain any PEM encoded certificates", t.CAFile)
+		}
+
+		config.RootCAs = pool
+	}
+
+	if t.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't load tls client certificate: %w", err)
+		}
+
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// checkPassword makes sure a single source doesn't provide both an inline password and a password file.
+func (c CredentialsConfig) checkPassword(source string) error {
+	if c.Password != "" && c.PasswordFile != "" {
+		return &ValidationError{Problems: []string{
+			fmt.Sprintf("%s provides both a password and a password file", source),
+		}}
+	}
+
+	return nil
+}
+
+// overridePassword drops the password setting of earlier sources, if the layer provides the other one.
+func (c *CredentialsConfig) overridePassword(layer CredentialsConfig) {
+	switch {
+	case layer.Password != "":
+		c.PasswordFile = ""
+	case layer.PasswordFile != "":
+		c.Password = ""
+	}
+}
+
+// readSecret reads a secret from a file, ignoring surrounding whitespace such as a trailing newline.
+func readSecret(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("couldn't read password file: %w", err)
+	}
+
+	secret := strings.TrimSpace(string(data))
+	if secret == "" {
+		return "", fmt.Errorf("password file %s is empty", path)
+	}
+
+	return secret, nil
+}
+
+// unmarshalYAML decodes YAML, rejecting unknown settings so that typos don't go unnoticed.
+func unmarshalYAML(data []byte, v interface{}) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(v); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// unmarshalJSON decodes JSON, rejecting unknown settings so that typos don't go unnoticed.
+func unmarshalJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	return decoder.Decode(v)
+}
+
+func logrusLevel(level log.Level) logrus.Level {
+	switch level {
+	case log.LevelDebug:
+		return logrus.DebugLevel
+	case log.LevelWarn:
+		return logrus.WarnLevel
+	case log.LevelError:
+		return logrus.ErrorLevel
+	default:
+		return logrus.InfoLevel
+	}
+}
@@ -0,0 +1,149 @@
+package config_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+	"tripica-client/config"
+	"tripica-client/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func lookup(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := env[name]
+
+		return v, ok
+	}
+}
+
+// nolint: funlen
+func TestLoad(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	dir := t.TempDir()
+
+	yamlFile := writeFile(t, dir, "tripica.yaml", `
+host: https://tripica.example.com
+credentials:
+  email: agent@example.com
+  password: from-yaml
+retry:
+  maxRetries: 2
+  waitTime: 500ms
+timeout: 3s
+log:
+  level: debug
+`)
+	jsonFile := writeFile(t, dir, "tripica.json", `{"retry":{"maxWaitTime":4000},"log":{"format":"json"}}`)
+	secretFile := writeFile(t, dir, "password", "from-file\n")
+
+	t.Run("sources are applied in order, later ones taking precedence", func(t *testing.T) {
+		cfg, err := config.Load(
+			config.FromFile(yamlFile),
+			config.FromFile(jsonFile),
+			config.FromLookup("TRIPICA_", lookup(map[string]string{
+				"TRIPICA_HOST":          "https://tripica.internal",
+				"TRIPICA_PASSWORD_FILE": secretFile,
+				"TRIPICA_TIMEOUT":       "2500",
+			})),
+		)
+		require.NoError(err)
+
+		assert.Equal("https://tripica.internal", cfg.Host)
+		assert.Equal("agent@example.com", cfg.Credentials.Email)
+		assert.Equal("from-file", cfg.Credentials.Password)
+		assert.Equal(uint(2), cfg.Retry.MaxRetries)
+		assert.Equal(config.Duration(500*time.Millisecond), cfg.Retry.WaitTime)
+		assert.Equal(config.Duration(4*time.Second), cfg.Retry.MaxWaitTime)
+		assert.Equal(config.Duration(2500*time.Millisecond), cfg.Timeout)
+		assert.Equal(log.LevelDebug, cfg.Log.Level)
+		assert.Equal(config.LogFormatJSON, cfg.Log.Format)
+		assert.Equal("https://tripica.internal", cfg.TriPicaConfig().Host)
+	})
+
+	t.Run("files override environment variables which are applied before them", func(t *testing.T) {
+		cfg, err := config.Load(
+			config.FromLookup("TRIPICA_", lookup(map[string]string{"TRIPICA_TIMEOUT": "2500"})),
+			config.FromFile(yamlFile),
+		)
+		require.NoError(err)
+
+		assert.Equal(config.Duration(3*time.Second), cfg.Timeout)
+	})
+
+	t.Run("hand-built configs are resolved", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.Host = "https://tripica.internal"
+		cfg.Credentials.Email = "agent@example.com"
+		cfg.Credentials.PasswordFile = secretFile
+
+		require.NoError(cfg.Resolve())
+		assert.Equal("from-file", cfg.Credentials.Password)
+	})
+
+	t.Run("all problems are reported at once", func(t *testing.T) {
+		_, err := config.Load(config.FromLookup("", lookup(map[string]string{
+			"HOST":          "tripica.example.com",
+			"RETRY_TIMEOUT": "ignored",
+			"TIMEOUT":       "0s",
+			"TLS_CERT_FILE": "cert.pem",
+			"LOG_LEVEL":     "verbose",
+		})))
+
+		var validationErr *config.ValidationError
+		require.True(errors.As(err, &validationErr))
+		assert.Equal([]string{
+			`host "tripica.example.com" needs to be an absolute http or https URL`,
+			"credentials require a password or password file",
+			"credentials require an email or alias",
+			"timeout 0s needs to be positive",
+			"tls cert file and key file need to be provided together",
+			`unknown log level "verbose"`,
+		}, validationErr.Problems)
+	})
+
+	t.Run("invalid environment values are descriptive", func(t *testing.T) {
+		_, err := config.Load(config.FromLookup("TRIPICA_", lookup(map[string]string{
+			"TRIPICA_RETRY_MAX_RETRIES": "-1",
+			"TRIPICA_LOG_DEBUG":         "maybe",
+		})))
+
+		assert.EqualError(err, `invalid triPica config: TRIPICA_RETRY_MAX_RETRIES needs to be a non-negative integer, `+
+			`got "-1"; TRIPICA_LOG_DEBUG needs to be a boolean, got "maybe"`)
+	})
+
+	t.Run("a source can't provide both a password and a password file", func(t *testing.T) {
+		path := writeFile(t, dir, "both.yaml", "credentials:\n  password: a\n  passwordFile: b\n")
+
+		_, err := config.Load(config.FromFile(path))
+		assert.EqualError(err, "invalid triPica config: "+path+" provides both a password and a password file")
+	})
+
+	t.Run("missing password files are reported", func(t *testing.T) {
+		_, err := config.Load(config.FromFile(yamlFile), config.FromLookup("TRIPICA_", lookup(map[string]string{
+			"TRIPICA_PASSWORD_FILE": filepath.Join(dir, "missing"),
+		})))
+		assert.Error(err)
+		assert.Contains(err.Error(), "couldn't read password file")
+	})
+
+	t.Run("unknown settings are rejected", func(t *testing.T) {
+		path := writeFile(t, dir, "typo.json", `{"hots":"https://tripica.example.com"}`)
+
+		_, err := config.Load(config.FromFile(path))
+		assert.Error(err)
+		assert.Contains(err.Error(), `unknown field "hots"`)
+	})
+}
@@ -0,0 +1,212 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"tripica-client/log"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Names of the environment variables read by FromEnv, following its prefix.
+const (
+	EnvHost                  = "HOST"
+	EnvEmail                 = "EMAIL"
+	EnvAlias                 = "ALIAS"
+	EnvPassword              = "PASSWORD"
+	EnvPasswordFile          = "PASSWORD_FILE"
+	EnvRetryMaxRetries       = "RETRY_MAX_RETRIES"
+	EnvRetryWaitTime         = "RETRY_WAIT_TIME"
+	EnvRetryMaxWaitTime      = "RETRY_MAX_WAIT_TIME"
+	EnvTimeout               = "TIMEOUT"
+	EnvTLSCAFile             = "TLS_CA_FILE"
+	EnvTLSCertFile           = "TLS_CERT_FILE"
+	EnvTLSKeyFile            = "TLS_KEY_FILE"
+	EnvTLSServerName         = "TLS_SERVER_NAME"
+	EnvTLSInsecureSkipVerify = "TLS_INSECURE_SKIP_VERIFY"
+	EnvLogLevel              = "LOG_LEVEL"
+	EnvLogFormat             = "LOG_FORMAT"
+	EnvLogDebug              = "LOG_DEBUG"
+)
+
+// Duration is a time.Duration which is configured either as a Go duration string, e.g. "1.5s",
+// or as a number of milliseconds.
+type Duration time.Duration
+
+// Milliseconds returns the duration as an integer millisecond count.
+func (d Duration) Milliseconds() int64 {
+	return time.Duration(d).Milliseconds()
+}
+
+// String returns the duration formatted as a Go duration string.
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// ParseDuration parses a Go duration string, or a number of milliseconds.
+func ParseDuration(s string) (Duration, error) {
+	s = strings.TrimSpace(s)
+
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return Duration(time.Duration(ms) * time.Millisecond), nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q, expected e.g. \"1.5s\" or a number of milliseconds", s)
+	}
+
+	return Duration(d), nil
+}
+
+// MarshalJSON encodes the duration as a Go duration string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes a Go duration string, or a number of milliseconds.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+
+	parsed, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+
+	return nil
+}
+
+// UnmarshalYAML decodes a Go duration string, or a number of milliseconds.
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	parsed, err := ParseDuration(node.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+
+	*d = parsed
+
+	return nil
+}
+
+// FromEnv reads the settings of environment variables, whose names consist of the prefix followed
+// by one of the Env constants, e.g. "TRIPICA_HOST". Variables which aren't set leave the settings unchanged.
+func FromEnv(prefix string) Source {
+	return FromLookup(prefix, os.LookupEnv)
+}
+
+// FromLookup reads the settings like FromEnv, but looks variables up using the provided function.
+func FromLookup(prefix string, lookup func(string) (string, bool)) Source {
+	return func(c *Config) error {
+		e := envReader{prefix: prefix, lookup: lookup}
+
+		layer := CredentialsConfig{
+			Password:     e.string(EnvPassword, &c.Credentials.Password),
+			PasswordFile: e.string(EnvPasswordFile, &c.Credentials.PasswordFile),
+		}
+
+		if err := layer.checkPassword("environment"); err != nil {
+			return err
+		}
+
+		c.Credentials.overridePassword(layer)
+
+		e.string(EnvHost, &c.Host)
+		e.string(EnvEmail, &c.Credentials.Email)
+		e.string(EnvAlias, &c.Credentials.Alias)
+		e.uint(EnvRetryMaxRetries, &c.Retry.MaxRetries)
+		e.duration(EnvRetryWaitTime, &c.Retry.WaitTime)
+		e.duration(EnvRetryMaxWaitTime, &c.Retry.MaxWaitTime)
+		e.duration(EnvTimeout, &c.Timeout)
+		e.string(EnvTLSCAFile, &c.TLS.CAFile)
+		e.string(EnvTLSCertFile, &c.TLS.CertFile)
+		e.string(EnvTLSKeyFile, &c.TLS.KeyFile)
+		e.string(EnvTLSServerName, &c.TLS.ServerName)
+		e.bool(EnvTLSInsecureSkipVerify, &c.TLS.InsecureSkipVerify)
+		e.string(EnvLogFormat, &c.Log.Format)
+		e.bool(EnvLogDebug, &c.Log.Debug)
+
+		var level string
+		if e.string(EnvLogLevel, &level) != "" {
+			c.Log.Level = log.Level(strings.ToLower(level))
+		}
+
+		if len(e.problems) > 0 {
+			return &ValidationError{Problems: e.problems}
+		}
+
+		return nil
+	}
+}
+
+// envReader reads environment variables into settings, collecting the problems of invalid values.
+type envReader struct {
+	prefix   string
+	lookup   func(string) (string, bool)
+	problems []string
+}
+
+// string sets the setting if the variable isn't empty, and returns the variable.
+func (e *envReader) string(name string, setting *string) string {
+	v, ok := e.lookup(e.prefix + name)
+	if !ok || v == "" {
+		return ""
+	}
+
+	*setting = v
+
+	return v
+}
+
+func (e *envReader) uint(name string, setting *uint) {
+	var v string
+	if e.string(name, &v) == "" {
+		return
+	}
+
+	parsed, err := strconv.ParseUint(strings.TrimSpace(v), 10, 32)
+	if err != nil {
+		e.problems = append(e.problems, fmt.Sprintf("%s%s needs to be a non-negative integer, got %q", e.prefix, name, v))
+
+		return
+	}
+
+	*setting = uint(parsed)
+}
+
+func (e *envReader) bool(name string, setting *bool) {
+	var v string
+	if e.string(name, &v) == "" {
+		return
+	}
+
+	parsed, err := strconv.ParseBool(strings.TrimSpace(v))
+	if err != nil {
+		e.problems = append(e.problems, fmt.Sprintf("%s%s needs to be a boolean, got %q", e.prefix, name, v))
+
+		return
+	}
+
+	*setting = parsed
+}
+
+func (e *envReader) duration(name string, setting *Duration) {
+	var v string
+	if e.string(name, &v) == "" {
+		return
+	}
+
+	parsed, err := ParseDuration(v)
+	if err != nil {
+		e.problems = append(e.problems, fmt.Sprintf("%s%s: %s", e.prefix, name, err))
+
+		return
+	}
+
+	*setting = parsed
+}
@@ -0,0 +1,462 @@
+package tripica
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	gohttp "net/http"
+	"strings"
+	"time"
+)
+
+// addressMoveRollbackTimeout limits the rollback of a failed address move, which isn't bound to the context
+// of the move, so that a cancelled move is still rolled back.
+const addressMoveRollbackTimeout = 30 * time.Second
+
+type (
+	// IndividualPatch represents a request changing some attributes of a triPica individual. Nil attributes
+	// are unchanged.
+	IndividualPatch struct {
+		Version  int     `json:"version"`
+		Title    *string `json:"title,omitempty"`
+		Name     *string `json:"givenName,omitempty"`
+		LastName *string `json:"familyName,omitempty"`
+		Gender   *string `json:"gender,omitempty"`
+	}
+
+	// ContactMediumRequest represents a request adding a contact medium to an individual. StartDateTime is required,
+	// and may lie in the future, e.g. for an announced move. An unset EndDateTime leaves the contact medium valid
+	// until it is ended. Phone numbers need to be international, and are sent in E.164 format.
+	ContactMediumRequest struct {
+		Type          ContactMediumType
+		Preferred     bool
+		StartDateTime time.Time
+		EndDateTime   time.Time
+		Medium        Medium
+	}
+
+	// ContactMediumPatch represents a request changing some attributes of a contact medium. Nil attributes are
+	// unchanged. The validity of a contact medium is changed by EndContactMedium instead.
+	ContactMediumPatch struct {
+		Preferred *bool   `json:"prefered,omitempty"`
+		Medium    *Medium `json:"medium,omitempty"`
+	}
+
+	// AddressMove represents the move of an individual to a new address, effective at EffectiveAt.
+	// Types defaults to both the delivery and the billing address.
+	AddressMove struct {
+		Address     MediumTypeAddress
+		Types       []ContactMediumType
+		EffectiveAt time.Time
+	}
+
+	// AddressMoveResult lists the contact mediums started and ended by an address move.
+	AddressMoveResult struct {
+		Started []ContactMedium
+		Ended   []ContactMedium
+	}
+
+	// AddressMoveError is returned by MoveAddress if a move failed and couldn't be rolled back completely.
+	// It wraps the error which made the move fail, and matches the errors of the failed compensations.
+	AddressMoveError struct {
+		PartyOUID      string
+		Cause          error
+		RollbackErrors []error
+	}
+)
+
+// Validate returns a ValidationError listing all invalid fields of the patch.
+func (p *IndividualPatch) Validate() error {
+	var errs fieldErrors
+
+	validateVersion(&errs, p.Version)
+
+	if p.Name != nil {
+		errs.require("givenName", *p.Name)
+	}
+
+	if p.LastName != nil {
+		errs.require("familyName", *p.LastName)
+	}
+
+	return errs.err()
+}
+
+// Validate returns a ValidationError listing all invalid fields of the request.
+func (r *ContactMediumRequest) Validate() error {
+	var errs fieldErrors
+
+	errs.require("type", string(r.Type))
+
+	if r.StartDateTime.IsZero() {
+		errs.add("startDateTime", FieldErrorRequired, "is required")
+	}
+
+	if !r.EndDateTime.IsZero() && !r.EndDateTime.After(r.StartDateTime) {
+		errs.add("endDateTime", FieldErrorInvalid, "needs to be after startDateTime")
+	}
+
+	switch {
+	case isAddressType(r.Type) && !r.Medium.IsAddress():
+		errs.add("medium.type", FieldErrorInvalid, fmt.Sprintf("needs to be an address for %s", r.Type))
+	case isAddressType(r.Type):
+		validateAddress(&errs, "medium.", r.Medium.MediumTypeAddress)
+	case r.Medium.IsEmail() && !strings.Contains(r.Medium.EmailAddress, "@"):
+		errs.add("medium.emailAddress", FieldErrorInvalid, "needs to be an email address")
+	case r.Medium.IsPhone():
+		if _, err := r.Medium.E164(""); err != nil {
+			errs.add("medium.number", FieldErrorInvalid, err.Error())
+		}
+	}
+
+	return errs.err()
+}
+
+// Validate returns a ValidationError listing all invalid fields of the move.
+func (m *AddressMove) Validate() error {
+	var errs fieldErrors
+
+	if m.EffectiveAt.IsZero() {
+		errs.add("effectiveAt", FieldErrorRequired, "is required")
+	}
+
+	for _, typ := range m.types() {
+		if !isAddressType(typ) {
+			errs.add("types", FieldErrorInvalid, fmt.Sprintf("%s isn't an address type", typ))
+		}
+	}
+
+	validateAddress(&errs, "address.", m.Address)
+
+	return errs.err()
+}
+
+// types returns the contact medium types the move applies to.
+func (m *AddressMove) types() []ContactMediumType {
+	if len(m.Types) == 0 {
+		return []ContactMediumType{ContactMediumTypeDeliveryAddress, ContactMediumTypeBillingAddress}
+	}
+
+	return m.Types
+}
+
+// UpdateIndividual changes the attributes set in the patch, e.g. the names or the title, leaving the others
+// unchanged, and returns the updated individual. The patch's Version needs to be the version of the individual
+// it is based on, otherwise a VersionConflictError is returned.
+func (i *individualAPI) UpdateIndividual(
+	ctx context.Context,
+	partyOUID string,
+	patch *IndividualPatch,
+) (*Individual, error) {
+	if err := patch.Validate(); err != nil {
+		return nil, NewTriPicaError(fmt.Errorf("couldn't update individual with partyOUID %s: %w", partyOUID, err))
+	}
+
+	var individual Individual
+	if err := (mutation{
+		method:      gohttp.MethodPatch,
+		url:         fmt.Sprintf(i.address+i.endpoints.path(EndpointIndividualByPartyOUID), partyOUID),
+		endpoint:    i.endpoints.path(EndpointIndividualByPartyOUID),
+		body:        patch,
+		resource:    "individual",
+		ouid:        partyOUID,
+		version:     patch.Version,
+		versioned:   true,
+		description: "update individual with partyOUID " + partyOUID,
+	}).send(ctx, i.httpClient, i.decoder, &individual); err != nil {
+		return nil, err
+	}
+
+	return &individual, nil
+}
+
+// AddContactMedium adds a contact medium to the individual, returning the created contact medium.
+func (i *individualAPI) AddContactMedium(
+	ctx context.Context,
+	partyOUID string,
+	req *ContactMediumRequest,
+) (*ContactMedium, error) {
+	if err := req.Validate(); err != nil {
+		return nil, NewTriPicaError(
+			fmt.Errorf("couldn't add contact medium to individual with partyOUID %s: %w", partyOUID, err),
+		)
+	}
+
+	body := &ContactMedium{
+		Preferred:     req.Preferred,
+		Type:          req.Type,
+		StartDateTime: NewDate(req.StartDateTime),
+		Medium:        req.Medium,
+	}
+
+	if !req.EndDateTime.IsZero() {
+		body.EndDateTime = NewDate(req.EndDateTime)
+	}
+
+	if body.Medium.IsPhone() {
+		number, err := body.Medium.E164("")
+		if err != nil {
+			return nil, NewTriPicaError(
+				fmt.Errorf("couldn't add contact medium to individual with partyOUID %s: %w", partyOUID, err),
+			)
+		}
+
+		body.Medium.Number = number
+	}
+
+	var medium ContactMedium
+	if err := (mutation{
+		method:      gohttp.MethodPost,
+		url:         fmt.Sprintf(i.address+i.endpoints.path(EndpointIndividualContactMediums), partyOUID),
+		endpoint:    i.endpoints.path(EndpointIndividualContactMediums),
+		body:        body,
+		resource:    "contact medium",
+		description: "add contact medium to individual with partyOUID " + partyOUID,
+	}).send(ctx, i.httpClient, i.decoder, &medium); err != nil {
+		return nil, err
+	}
+
+	return &medium, nil
+}
+
+// UpdateContactMedium changes the attributes set in the patch, returning the updated contact medium.
+func (i *individualAPI) UpdateContactMedium(
+	ctx context.Context,
+	partyOUID, contactMediumOUID string,
+	patch *ContactMediumPatch,
+) (*ContactMedium, error) {
+	return i.modifyContactMedium(ctx, partyOUID, contactMediumOUID, patch, "update")
+}
+
+// EndContactMedium ends the validity of the individual's contact medium at the provided time, which may lie
+// in the future, returning the ended contact medium.
+func (i *individualAPI) EndContactMedium(
+	ctx context.Context,
+	partyOUID, contactMediumOUID string,
+	at time.Time,
+) (*ContactMedium, error) {
+	var errs fieldErrors
+	if at.IsZero() {
+		errs.add("endDateTime", FieldErrorRequired, "is required")
+	}
+
+	if err := errs.err(); err != nil {
+		return nil, NewTriPicaError(fmt.Errorf("couldn't end contact medium with ouid %s: %w", contactMediumOUID, err))
+	}
+
+	return i.modifyContactMedium(ctx, partyOUID, contactMediumOUID, validityEnd{EndDateTime: NewDate(at)}, "end")
+}
+
+// MoveAddress moves the individual to a new address: for each of the move's types, a contact medium with the new
+// address is started at EffectiveAt, and the contact medium valid at that time is ended, so that there is neither
+// a gap nor an overlap. Preferred and the medium type are carried over from the ended contact medium.
+//
+// The move is atomic from the caller's point of view: if any step fails, the contact mediums already started are
+// ended at their start, and the ones already ended are restored, before the error is returned. If the compensation
+// fails as well, an AddressMoveError wrapping the errors of both is returned, and the individual needs to be
+// repaired manually. The rollback isn't cancelled with ctx, but limited by a timeout of its own.
+// Moves conflicting with a contact medium which starts after EffectiveAt, e.g. an already scheduled move,
+// are rejected.
+func (i *individualAPI) MoveAddress(
+	ctx context.Context,
+	partyOUID string,
+	move *AddressMove,
+) (*AddressMoveResult, error) {
+	if err := move.Validate(); err != nil {
+		return nil, NewTriPicaError(fmt.Errorf("couldn't move individual with partyOUID %s: %w", partyOUID, err))
+	}
+
+	individual, err := i.getIndividualByPartyOUID(ctx, partyOUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if individual == nil {
+		return nil, NewTriPicaError(fmt.Errorf("couldn't move individual with partyOUID %s: not found", partyOUID))
+	}
+
+	var (
+		errs     fieldErrors
+		requests = make([]*ContactMediumRequest, 0, len(move.types()))
+		replaced []ContactMedium
+	)
+
+	for _, typ := range move.types() {
+		req := &ContactMediumRequest{
+			Type:          typ,
+			StartDateTime: move.EffectiveAt,
+			Medium:        Medium{MediumTypeAddress: move.Address},
+		}
+
+		for _, cm := range individual.ContactMediums {
+			switch {
+			case cm.Type != typ:
+			case cm.StartDateTime.After(move.EffectiveAt):
+				errs.add("effectiveAt", FieldErrorInvalid, fmt.Sprintf(
+					"conflicts with %s contact medium %s starting later", typ, cm.OUID,
+				))
+			case cm.Validity().Contains(move.EffectiveAt):
+				req.Preferred = cm.Preferred
+				req.Medium.Type = cm.Medium.Type
+				replaced = append(replaced, cm)
+			}
+		}
+
+		requests = append(requests, req)
+	}
+
+	if err := errs.err(); err != nil {
+		return nil, NewTriPicaError(fmt.Errorf("couldn't move individual with partyOUID %s: %w", partyOUID, err))
+	}
+
+	result := &AddressMoveResult{}
+
+	for _, req := range requests {
+		started, err := i.AddContactMedium(ctx, partyOUID, req)
+		if err != nil {
+			return nil, i.rollbackAddressMove(partyOUID, result.Started, nil, err)
+		}
+
+		result.Started = append(result.Started, *started)
+	}
+
+	for j, cm := range replaced {
+		ended, err := i.EndContactMedium(ctx, partyOUID, cm.OUID, move.EffectiveAt)
+		if err != nil {
+			return nil, i.rollbackAddressMove(partyOUID, result.Started, replaced[:j], err)
+		}
+
+		result.Ended = append(result.Ended, *ended)
+	}
+
+	return result, nil
+}
+
+// rollbackAddressMove compensates the steps of an address move which already succeeded, in reverse order:
+// the replaced contact mediums get their previous end restored, and the started ones are ended at their start.
+// It returns the cause of the rollback, or an AddressMoveError if the compensation failed.
+func (i *individualAPI) rollbackAddressMove(partyOUID string, started, replaced []ContactMedium, cause error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), addressMoveRollbackTimeout)
+	defer cancel()
+
+	var failures []error
+
+	for j := len(replaced) - 1; j >= 0; j-- {
+		cm := replaced[j]
+
+		_, err := i.modifyContactMedium(ctx, partyOUID, cm.OUID, validityEnd{EndDateTime: cm.EndDateTime}, "restore")
+		if err != nil {
+			failures = append(failures, err)
+		}
+	}
+
+	for j := len(started) - 1; j >= 0; j-- {
+		cm := started[j]
+
+		_, err := i.modifyContactMedium(ctx, partyOUID, cm.OUID, validityEnd{EndDateTime: cm.StartDateTime}, "revoke")
+		if err != nil {
+			failures = append(failures, err)
+		}
+	}
+
+	if len(failures) == 0 {
+		return fmt.Errorf("couldn't move individual with partyOUID %s, changes were rolled back: %w", partyOUID, cause)
+	}
+
+	moveErr := &AddressMoveError{PartyOUID: partyOUID, Cause: cause, RollbackErrors: failures}
+
+	i.logger.WithFields(map[string]interface{}{
+		"party_ouid":     partyOUID,
+		"error":          cause.Error(),
+		"rollback_error": moveErr.rollbackError(),
+	}).Error("couldn't roll back address move")
+
+	return moveErr
+}
+
+// Error makes AddressMoveError implement the error interface.
+func (e *AddressMoveError) Error() string {
+	return fmt.Sprintf(
+		"couldn't move individual with partyOUID %s: %s, nor roll back the changes: %s",
+		e.PartyOUID, e.Cause, e.rollbackError(),
+	)
+}
+
+// Unwrap returns the error which made the move fail.
+func (e *AddressMoveError) Unwrap() error {
+	return e.Cause
+}
+
+// Is determines whether the cause or any error of the rollback matches the target, see errors.Is.
+func (e *AddressMoveError) Is(target error) bool {
+	if errors.Is(e.Cause, target) {
+		return true
+	}
+
+	for _, err := range e.RollbackErrors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first error matching the target, trying the cause before the errors of the rollback,
+// see errors.As.
+func (e *AddressMoveError) As(target interface{}) bool {
+	if errors.As(e.Cause, target) {
+		return true
+	}
+
+	for _, err := range e.RollbackErrors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e *AddressMoveError) rollbackError() string {
+	failures := make([]string, 0, len(e.RollbackErrors))
+	for _, err := range e.RollbackErrors {
+		failures = append(failures, err.Error())
+	}
+
+	return strings.Join(failures, "; ")
+}
+
+// modifyContactMedium sends a patch of the contact medium, returning the updated contact medium.
+func (i *individualAPI) modifyContactMedium(
+	ctx context.Context,
+	partyOUID, contactMediumOUID string,
+	body interface{},
+	action string,
+) (*ContactMedium, error) {
+	var medium ContactMedium
+	if err := (mutation{
+		method:      gohttp.MethodPatch,
+		url:         fmt.Sprintf(i.address+i.endpoints.path(EndpointIndividualContactMedium), partyOUID, contactMediumOUID),
+		endpoint:    i.endpoints.path(EndpointIndividualContactMedium),
+		body:        body,
+		resource:    "contact medium",
+		ouid:        contactMediumOUID,
+		description: action + " contact medium with ouid " + contactMediumOUID,
+	}).send(ctx, i.httpClient, i.decoder, &medium); err != nil {
+		return nil, err
+	}
+
+	return &medium, nil
+}
+
+func isAddressType(typ ContactMediumType) bool {
+	return typ == ContactMediumTypeDeliveryAddress || typ == ContactMediumTypeBillingAddress
+}
+
+func validateAddress(errs *fieldErrors, prefix string, address MediumTypeAddress) {
+	errs.require(prefix+"street1", address.Street1)
+	errs.require(prefix+"postCode", address.Postcode)
+	errs.require(prefix+"city", address.City)
+	errs.require(prefix+"country", address.Country)
+}
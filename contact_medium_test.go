@@ -0,0 +1,268 @@
+package tripica
+
+import (
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"io"
+	gohttp "net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"tripica-client/http/errors"
+	"tripica-client/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const moveIndividualPath = "/api/private/v1/agent/individual/P1"
+
+// moveIndividual has a preferred delivery address valid until further notice, and a billing address
+// ending in 2023.
+const moveIndividual = `{"ouid":"P1","contactMediums":[
+	{"ouid":"D1","type":"DELIVERY_ADDRESS","prefered":true,"startDateTime":1500000000000,
+		"medium":{"type":"POSTAL_ADDRESS","street1":"Alt 1","postCode":"10115","city":"Berlin","country":"DE"}},
+	{"ouid":"B1","type":"BILLING_ADDRESS","startDateTime":1500000000000,"endDateTime":1700000000000,
+		"medium":{"street1":"Alt 1","postCode":"10115","city":"Berlin","country":"DE"}}
+]}`
+
+// moveServer records the mutations of contact mediums, failing the ones matched by fail.
+type moveServer struct {
+	mux        sync.Mutex
+	individual string
+	started    int
+	mutations  []string
+	fail       func(method, ouid string) bool
+}
+
+func (s *moveServer) ServeHTTP(w gohttp.ResponseWriter, r *gohttp.Request) {
+	if r.Method == gohttp.MethodGet && r.URL.Path == moveIndividualPath {
+		_, _ = w.Write([]byte(s.individual))
+
+		return
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	body := map[string]interface{}{}
+	raw, _ := io.ReadAll(r.Body)
+	_ = json.Unmarshal(raw, &body)
+
+	ouid := strings.TrimPrefix(r.URL.Path, moveIndividualPath+"/contactMedium")
+	if ouid = strings.TrimPrefix(ouid, "/"); ouid == "" {
+		s.started++
+		ouid = fmt.Sprintf("N%d", s.started)
+	}
+
+	s.mutations = append(s.mutations, fmt.Sprintf("%s %s %s", r.Method, ouid, raw))
+
+	if s.fail != nil && s.fail(r.Method, ouid) {
+		w.WriteHeader(gohttp.StatusInternalServerError)
+
+		return
+	}
+
+	body["ouid"] = ouid
+	response, _ := json.Marshal(body)
+	_, _ = w.Write(response)
+}
+
+func (s *moveServer) calls() []string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return append([]string(nil), s.mutations...)
+}
+
+func TestIndividualAPI_MoveAddress(t *testing.T) {
+	assert := assert.New(t)
+
+	effectiveAt := time.UnixMilli(1600000000000)
+	move := &AddressMove{
+		Address:     MediumTypeAddress{Street1: "Neu 2", Postcode: "80331", City: "München", Country: "DE"},
+		EffectiveAt: effectiveAt,
+	}
+
+	const (
+		end     = `{"endDateTime":1600000000000}`
+		address = `"city":"München","country":"DE","street1":"Neu 2","postCode":"80331"`
+	)
+
+	t.Run("the addresses are replaced", func(t *testing.T) {
+		srv := &moveServer{individual: moveIndividual}
+		client := newTestClient(t, srv, nil)
+
+		result, err := client.MoveAddress(context.Background(), "P1", move)
+
+		require.NoError(t, err)
+		assert.Equal([]string{
+			`POST N1 {"prefered":true,"type":"DELIVERY_ADDRESS","startDateTime":1600000000000,"endDateTime":null,` +
+				`"medium":{` + address + `,"type":"POSTAL_ADDRESS"}}`,
+			`POST N2 {"prefered":false,"type":"BILLING_ADDRESS","startDateTime":1600000000000,"endDateTime":null,` +
+				`"medium":{` + address + `,"type":""}}`,
+			"PATCH D1 " + end,
+			"PATCH B1 " + end,
+		}, srv.calls())
+
+		require.Len(t, result.Started, 2)
+		assert.Equal("N1", result.Started[0].OUID)
+		assert.True(result.Started[0].Preferred)
+		assert.Equal("N2", result.Started[1].OUID)
+
+		require.Len(t, result.Ended, 2)
+		assert.Equal("D1", result.Ended[0].OUID)
+		assert.Equal("B1", result.Ended[1].OUID)
+	})
+
+	t.Run("started contact mediums are revoked if another one can't be started", func(t *testing.T) {
+		srv := &moveServer{individual: moveIndividual, fail: func(method, ouid string) bool {
+			return ouid == "N2"
+		}}
+		client := newTestClient(t, srv, nil)
+
+		result, err := client.MoveAddress(context.Background(), "P1", move)
+
+		assert.Nil(result)
+		require.Error(t, err)
+		assert.Contains(err.Error(), "changes were rolled back")
+
+		var httpErr *errors.HTTPError
+		require.True(t, goerrors.As(err, &httpErr))
+		assert.Equal(gohttp.StatusInternalServerError, httpErr.StatusCode)
+
+		calls := srv.calls()
+		require.Len(t, calls, 3)
+		assert.Equal("PATCH N1 "+end, calls[2], "N1 is ended at its start")
+	})
+
+	t.Run("ended contact mediums are restored if another one can't be ended", func(t *testing.T) {
+		srv := &moveServer{individual: moveIndividual, fail: func(method, ouid string) bool {
+			return method == gohttp.MethodPatch && ouid == "B1"
+		}}
+		client := newTestClient(t, srv, nil)
+
+		result, err := client.MoveAddress(context.Background(), "P1", move)
+
+		assert.Nil(result)
+		require.Error(t, err)
+		assert.Contains(err.Error(), "changes were rolled back")
+
+		calls := srv.calls()
+		require.Len(t, calls, 7)
+		assert.Equal([]string{
+			"PATCH D1 " + end,
+			"PATCH B1 " + end,
+			`PATCH D1 {"endDateTime":null}`,
+			"PATCH N2 " + end,
+			"PATCH N1 " + end,
+		}, calls[2:])
+	})
+
+	t.Run("failed rollbacks are reported", func(t *testing.T) {
+		logger := log.NewCapturingLogger()
+		srv := &moveServer{individual: moveIndividual, fail: func(method, ouid string) bool {
+			return strings.HasPrefix(ouid, "N") && (method == gohttp.MethodPatch || ouid == "N2")
+		}}
+		client := newTestClient(t, srv, logger)
+
+		result, err := client.MoveAddress(context.Background(), "P1", move)
+
+		assert.Nil(result)
+		require.Error(t, err)
+		assert.Contains(err.Error(), "nor roll back the changes")
+		assert.Contains(err.Error(), "revoke contact medium with ouid N1")
+
+		var moveErr *AddressMoveError
+		require.True(t, goerrors.As(err, &moveErr))
+		assert.Equal("P1", moveErr.PartyOUID)
+		require.Len(t, moveErr.RollbackErrors, 1)
+
+		var httpErr *errors.HTTPError
+		require.True(t, goerrors.As(moveErr.RollbackErrors[0], &httpErr))
+		assert.Equal(gohttp.StatusInternalServerError, httpErr.StatusCode)
+
+		entries := logger.EntriesWithLevel(log.LevelError)
+		require.Len(t, entries, 1)
+		assert.Equal("couldn't roll back address move", entries[0].Message)
+		assert.Equal("P1", entries[0].Fields["party_ouid"])
+	})
+
+	t.Run("cancelled moves are still rolled back", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		srv := &moveServer{individual: moveIndividual, fail: func(method, ouid string) bool {
+			if ouid != "N2" {
+				return false
+			}
+
+			cancel()
+
+			return true
+		}}
+		client := newTestClient(t, srv, nil)
+
+		result, err := client.MoveAddress(ctx, "P1", move)
+
+		assert.Nil(result)
+		require.Error(t, err)
+		assert.Contains(err.Error(), "changes were rolled back")
+
+		calls := srv.calls()
+		require.Len(t, calls, 3)
+		assert.Equal("PATCH N1 "+end, calls[2])
+	})
+
+	t.Run("moves conflicting with later contact mediums are rejected", func(t *testing.T) {
+		srv := &moveServer{individual: `{"ouid":"P1","contactMediums":[
+			{"ouid":"D2","type":"DELIVERY_ADDRESS","startDateTime":1650000000000,"medium":{}}
+		]}`}
+		client := newTestClient(t, srv, nil)
+
+		result, err := client.MoveAddress(context.Background(), "P1", move)
+
+		assert.Nil(result)
+
+		var validationErr *ValidationError
+		require.True(t, goerrors.As(err, &validationErr))
+		assert.Equal("effectiveAt", validationErr.Fields[0].Field)
+		assert.Contains(validationErr.Fields[0].Message, "D2")
+		assert.Empty(srv.calls())
+	})
+}
+
+func TestMedium_IsAddress(t *testing.T) {
+	tests := []struct {
+		typ  string
+		want bool
+	}{
+		{typ: "", want: true},
+		{typ: MediumKindPostalAddress, want: true},
+		{typ: "postal_address", want: true},
+		{typ: MediumKindEmail, want: false},
+		{typ: MediumKindMobile, want: false},
+		{typ: "PIGEON", want: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.typ, func(t *testing.T) {
+			medium := &Medium{Type: tt.typ}
+			assert.Equal(t, tt.want, medium.IsAddress())
+		})
+	}
+}
+
+func TestMedium_MarshalJSON(t *testing.T) {
+	email, err := json.Marshal(Medium{
+		Type:            MediumKindEmail,
+		MediumTypeEmail: MediumTypeEmail{EmailAddress: "max@example.com"},
+	})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"EMAIL","emailAddress":"max@example.com"}`, string(email), "no address fields are sent")
+}
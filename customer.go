@@ -1,7 +1,7 @@
 package tripica
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	gohttp "net/http"
 	"tripica-client/http"
@@ -9,23 +9,36 @@ import (
 )
 
 const (
-	customerBasePath = "/api/private/v1/agent/customer"
+	customerBasePath = "/api/private/{version}/agent/customer"
 
+	customerPathCreate    = ""
 	customerPathGetByName = "/name/%s"
 	customerPathGetByOUID = "/%s"
 )
 
+// Possible customer statuses.
+const (
+	CustomerStatusActive   = "ACTIVE"
+	CustomerStatusInactive = "INACTIVE"
+)
+
 // Customer manages customer related endpoints within triPica.
 type customerAPI struct {
 	httpClient *http.Client
 	address    string
+	endpoints  *endpointRegistry
+	decoder    *decoder
 }
 
 // GetCustomerByOUID retrieves the customer using provided OUID.
 func (c *customerAPI) GetCustomerByOUID(ouid string) (*Customer, error) {
-	url := fmt.Sprintf(c.address+customerPathGetByOUID, ouid)
+	return c.getCustomerByOUID(context.Background(), ouid)
+}
 
-	resp, err := c.httpClient.Get(url)
+func (c *customerAPI) getCustomerByOUID(ctx context.Context, ouid string) (*Customer, error) {
+	url := fmt.Sprintf(c.address+c.endpoints.path(EndpointCustomerByOUID), ouid)
+
+	resp, err := c.httpClient.Get(url, http.WithContext(ctx))
 	if err != nil {
 		return nil, NewTriPicaError(errors.NewHTTPRequestError(err))
 	}
@@ -44,7 +57,7 @@ func (c *customerAPI) GetCustomerByOUID(ouid string) (*Customer, error) {
 	}
 
 	var customer Customer
-	if err := json.Unmarshal(resp.Body(), &customer); err != nil {
+	if err := c.decoder.decode(c.endpoints.path(EndpointCustomerByOUID), resp.Body(), &customer); err != nil {
 		return nil, NewTriPicaError(errors.NewParseError(err, resp.Body()))
 	}
 
@@ -53,7 +66,7 @@ func (c *customerAPI) GetCustomerByOUID(ouid string) (*Customer, error) {
 
 // GetCustomerByName retrieves a customer by the customerName <=> customer's external ID.
 func (c *customerAPI) GetCustomerByName(customerName string) (*Customer, error) {
-	url := fmt.Sprintf(c.address+customerPathGetByName, customerName)
+	url := fmt.Sprintf(c.address+c.endpoints.path(EndpointCustomerByName), customerName)
 
 	resp, err := c.httpClient.Get(url)
 	if err != nil {
@@ -74,30 +87,187 @@ func (c *customerAPI) GetCustomerByName(customerName string) (*Customer, error)
 	}
 
 	var customer Customer
-	if err := json.Unmarshal(resp.Body(), &customer); err != nil {
+	if err := c.decoder.decode(c.endpoints.path(EndpointCustomerByName), resp.Body(), &customer); err != nil {
 		return nil, NewTriPicaError(errors.NewParseError(err, resp.Body()))
 	}
 
 	return &customer, nil
 }
 
+// CreateCustomer creates a customer for the referenced party, returning the created customer.
+func (c *customerAPI) CreateCustomer(ctx context.Context, req *CustomerCreateRequest) (*Customer, error) {
+	if err := req.Validate(); err != nil {
+		return nil, NewTriPicaError(fmt.Errorf("couldn't create customer: %w", err))
+	}
+
+	var customer Customer
+	if err := (mutation{
+		method:      gohttp.MethodPost,
+		url:         c.address + c.endpoints.path(EndpointCustomerCreate),
+		endpoint:    c.endpoints.path(EndpointCustomerCreate),
+		body:        req,
+		resource:    "customer",
+		description: "create customer with name " + req.Name,
+	}).send(ctx, c.httpClient, c.decoder, &customer); err != nil {
+		return nil, err
+	}
+
+	return &customer, nil
+}
+
+// UpdateCustomer replaces the attributes of the customer, returning the updated customer.
+// The request's Version needs to be the version of the customer it is based on, otherwise
+// a VersionConflictError is returned.
+func (c *customerAPI) UpdateCustomer(ctx context.Context, ouid string, req *CustomerUpdateRequest) (*Customer, error) {
+	if err := req.Validate(); err != nil {
+		return nil, NewTriPicaError(fmt.Errorf("couldn't update customer with ouid %s: %w", ouid, err))
+	}
+
+	return c.modify(ctx, gohttp.MethodPut, ouid, req.Version, req, "update")
+}
+
+// PatchCustomer changes the attributes set in the patch, leaving the others unchanged, and returns
+// the updated customer. The patch's Version needs to be the version of the customer it is based on,
+// otherwise a VersionConflictError is returned.
+func (c *customerAPI) PatchCustomer(ctx context.Context, ouid string, patch *CustomerPatch) (*Customer, error) {
+	if err := patch.Validate(); err != nil {
+		return nil, NewTriPicaError(fmt.Errorf("couldn't patch customer with ouid %s: %w", ouid, err))
+	}
+
+	return c.modify(ctx, gohttp.MethodPatch, ouid, patch.Version, patch, "patch")
+}
+
+// DeactivateCustomer sets the status of the customer to inactive, returning the updated customer.
+func (c *customerAPI) DeactivateCustomer(ctx context.Context, ouid string, version int) (*Customer, error) {
+	status := CustomerStatusInactive
+
+	return c.PatchCustomer(ctx, ouid, &CustomerPatch{Version: version, Status: &status})
+}
+
+func (c *customerAPI) modify(
+	ctx context.Context,
+	method, ouid string,
+	version int,
+	body interface{},
+	action string,
+) (*Customer, error) {
+	var customer Customer
+	if err := (mutation{
+		method:      method,
+		url:         fmt.Sprintf(c.address+c.endpoints.path(EndpointCustomerByOUID), ouid),
+		endpoint:    c.endpoints.path(EndpointCustomerByOUID),
+		body:        body,
+		resource:    "customer",
+		ouid:        ouid,
+		version:     version,
+		versioned:   true,
+		description: action + " customer with ouid " + ouid,
+	}).send(ctx, c.httpClient, c.decoder, &customer); err != nil {
+		return nil, err
+	}
+
+	return &customer, nil
+}
+
 // Customer represents a triPica customer.
 type Customer struct {
-	Name     string `json:"name"`
-	OUID     string `json:"ouid"`
-	PartyRef struct {
-		PartyOUID string `json:"partyOuid"`
-	} `json:"partyRef"`
+	Name         string                `json:"name" tripica:"required"`
+	OUID         string                `json:"ouid" tripica:"required"`
+	Version      int                   `json:"version"`
+	Status       string                `json:"status"`
+	PartyRef     PartyRef              `json:"partyRef" tripica:"required"`
 	PaymentMeans []CustomerPaymentMean `json:"paymentMeans"`
+	Extensions   Extensions            `json:"-"`
 }
 
-// CustomerPaymentMean represents a triPica customer mean of payment.
-type CustomerPaymentMean struct {
-	EndDateTime     Date                               `json:"endDateTime"`
-	Characteristics CustomerPaymentMeanCharacteristics `json:"characteristics"`
+// PartyRef references the party, i.e. the individual or organization, a customer belongs to.
+type PartyRef struct {
+	PartyOUID string `json:"partyOuid"`
 }
 
-// CustomerPaymentMeanCharacteristics represents Characteristics part of CustomerPaymentMean.
-type CustomerPaymentMeanCharacteristics struct {
-	IBAN string `json:"iban"`
+// CustomerCreateRequest represents a request creating a triPica customer. Status defaults to active.
+type CustomerCreateRequest struct {
+	Name     string   `json:"name"`
+	PartyRef PartyRef `json:"partyRef"`
+	Status   string   `json:"status,omitempty"`
+}
+
+// CustomerUpdateRequest represents a request replacing the attributes of a triPica customer.
+type CustomerUpdateRequest struct {
+	Version  int      `json:"version"`
+	Name     string   `json:"name"`
+	PartyRef PartyRef `json:"partyRef"`
+	Status   string   `json:"status"`
+}
+
+// CustomerPatch represents a request changing some attributes of a triPica customer. Nil attributes are unchanged.
+type CustomerPatch struct {
+	Version int     `json:"version"`
+	Name    *string `json:"name,omitempty"`
+	Status  *string `json:"status,omitempty"`
+}
+
+// Validate returns a ValidationError listing all invalid fields of the request.
+func (r *CustomerCreateRequest) Validate() error {
+	var errs fieldErrors
+
+	errs.require("name", r.Name)
+	errs.require("partyRef.partyOuid", r.PartyRef.PartyOUID)
+
+	if r.Status != "" {
+		validateCustomerStatus(&errs, r.Status)
+	}
+
+	return errs.err()
+}
+
+// Validate returns a ValidationError listing all invalid fields of the request.
+func (r *CustomerUpdateRequest) Validate() error {
+	var errs fieldErrors
+
+	validateVersion(&errs, r.Version)
+	errs.require("name", r.Name)
+	errs.require("partyRef.partyOuid", r.PartyRef.PartyOUID)
+	validateCustomerStatus(&errs, r.Status)
+
+	return errs.err()
+}
+
+// Validate returns a ValidationError listing all invalid fields of the patch.
+func (p *CustomerPatch) Validate() error {
+	var errs fieldErrors
+
+	validateVersion(&errs, p.Version)
+
+	if p.Name != nil {
+		errs.require("name", *p.Name)
+	}
+
+	if p.Status != nil {
+		validateCustomerStatus(&errs, *p.Status)
+	}
+
+	return errs.err()
+}
+
+func validateCustomerStatus(errs *fieldErrors, status string) {
+	if status != CustomerStatusActive && status != CustomerStatusInactive {
+		errs.add("status", FieldErrorInvalid, fmt.Sprintf(
+			"needs to be %s or %s", CustomerStatusActive, CustomerStatusInactive,
+		))
+	}
+}
+
+// UnmarshalJSON decodes the Customer, retaining undeclared properties in its Extensions.
+func (c *Customer) UnmarshalJSON(data []byte) error {
+	type customer Customer
+
+	return unmarshalExtended(data, (*customer)(c), &c.Extensions)
+}
+
+// MarshalJSON encodes the Customer, including its Extensions.
+func (c Customer) MarshalJSON() ([]byte, error) {
+	type customer Customer
+
+	return marshalExtended(customer(c), c.Extensions)
 }
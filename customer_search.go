@@ -0,0 +1,242 @@
+package tripica
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+	"tripica-client/iban"
+	"unicode"
+)
+
+const customerPathSearch = "/search"
+
+// Possible fields customers can be searched by.
+const (
+	// SearchByEmail matches the email of the customer's login.
+	SearchByEmail CustomerSearchField = "email"
+	// SearchByIBAN matches the IBAN of one of the customer's payment means. Spaces are ignored.
+	SearchByIBAN CustomerSearchField = "iban"
+	// SearchByFamilyName matches the family name of the customer's individual.
+	SearchByFamilyName CustomerSearchField = "familyName"
+	// SearchByPostcode matches the postcode of one of the customer's addresses.
+	SearchByPostcode CustomerSearchField = "postcode"
+)
+
+// Possible qualities of a search match, from the weakest to the strongest.
+const (
+	MatchNone MatchQuality = iota
+	MatchContains
+	MatchPrefix
+	MatchExact
+)
+
+type (
+	// CustomerSearchField represents a field customers can be searched by.
+	CustomerSearchField string
+
+	// MatchQuality represents how closely a matched value corresponds to the searched one.
+	MatchQuality int
+
+	// CustomerSearchQuery searches customers whose Field matches Value.
+	CustomerSearchQuery struct {
+		Field CustomerSearchField
+		Value string
+	}
+
+	// CustomerSearchResult represents a customer found by a search. Results are ranked by their Score,
+	// as computed by triPica, followed by the quality of the match.
+	CustomerSearchResult struct {
+		Customer  *Customer
+		Score     float64
+		Field     CustomerSearchField
+		Highlight Highlight
+	}
+
+	// Highlight locates the searched value within the matched value, e.g. "max@EXAMPLE.com" for "example".
+	// Start and End are byte offsets into Value, and are equal if the searched value couldn't be located.
+	Highlight struct {
+		Value   string
+		Start   int
+		End     int
+		Quality MatchQuality
+	}
+
+	// customerSearchHit represents a single hit returned by triPica's customer search.
+	customerSearchHit struct {
+		Customer     *Customer `json:"customer" tripica:"required"`
+		Score        float64   `json:"score"`
+		MatchedValue string    `json:"matchedValue"`
+	}
+)
+
+// Validate returns a ValidationError if the query can't be searched for.
+func (q CustomerSearchQuery) Validate() error {
+	var errs fieldErrors
+
+	value := normalizeSearchValue(q.Field, q.Value)
+
+	switch q.Field {
+	case SearchByEmail, SearchByIBAN, SearchByFamilyName, SearchByPostcode:
+	default:
+		errs.add("field", FieldErrorInvalid, "can't be searched by")
+
+		return errs.err()
+	}
+
+	switch {
+	case value == "":
+		errs.require("value", value)
+	case q.Field == SearchByEmail && !strings.Contains(value, "@"):
+		errs.add("value", FieldErrorInvalid, "needs to be an email address")
+	case q.Field == SearchByIBAN && strings.IndexFunc(value, isNotAlphanumeric) >= 0:
+		errs.add("value", FieldErrorInvalid, "can only contain letters, digits and spaces")
+	}
+
+	return errs.err()
+}
+
+// SearchCustomers lazily iterates over the customers matching the query, fetching pageSize customers at once.
+// The customers of each page are ranked, with the best matches first.
+//
+//	it := client.SearchCustomers(ctx, tripica.CustomerSearchQuery{Field: tripica.SearchByIBAN, Value: iban}, 20)
+func (c *customerAPI) SearchCustomers(
+	ctx context.Context,
+	query CustomerSearchQuery,
+	pageSize int,
+) *Iterator[*CustomerSearchResult] {
+	err := query.Validate()
+	if err != nil {
+		err = NewTriPicaError(err)
+	}
+
+	params := url.Values{string(query.Field): []string{normalizeSearchValue(query.Field, query.Value)}}
+	searchURL := c.address + c.endpoints.path(EndpointCustomerSearch) + "?" + params.Encode()
+
+	return newIterator(ctx, pageSize, func(ctx context.Context, offset, limit int) ([]*CustomerSearchResult, error) {
+		if err != nil {
+			return nil, err
+		}
+
+		hits, fetchErr := getPage[*customerSearchHit](
+			ctx, c.httpClient, c.decoder, searchURL, c.endpoints.path(EndpointCustomerSearch), offset, limit,
+			"customers by "+string(query.Field),
+		)
+		if fetchErr != nil {
+			return nil, fetchErr
+		}
+
+		return rankSearchHits(query, hits), nil
+	})
+}
+
+// rankSearchHits converts the hits into results, highlighting the matches, and sorts them by relevance.
+func rankSearchHits(query CustomerSearchQuery, hits []*customerSearchHit) []*CustomerSearchResult {
+	results := make([]*CustomerSearchResult, 0, len(hits))
+
+	for _, hit := range hits {
+		if hit == nil || hit.Customer == nil {
+			continue
+		}
+
+		results = append(results, &CustomerSearchResult{
+			Customer:  hit.Customer,
+			Score:     hit.Score,
+			Field:     query.Field,
+			Highlight: highlight(query, hit.MatchedValue),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+
+		return results[i].Highlight.Quality > results[j].Highlight.Quality
+	})
+
+	return results
+}
+
+// highlight locates the searched value within the matched one, ignoring case, and spaces within IBANs.
+func highlight(query CustomerSearchQuery, matched string) Highlight {
+	h := Highlight{Value: matched}
+
+	needle := normalizeSearchValue(query.Field, query.Value)
+	if needle == "" || matched == "" {
+		return h
+	}
+
+	// starts and ends map the bytes of the normalized matched value to the offsets of their runes in the matched value.
+	var (
+		haystack strings.Builder
+		starts   []int
+		ends     []int
+	)
+
+	for i, r := range matched {
+		if query.Field == SearchByIBAN && unicode.IsSpace(r) {
+			continue
+		}
+
+		s := strings.ToLower(string(r))
+		for j := 0; j < len(s); j++ {
+			starts = append(starts, i)
+			ends = append(ends, i+len(string(r)))
+		}
+
+		haystack.WriteString(s)
+	}
+
+	// Lowercasing may change the length of the needle, e.g. for "ẞ" or the Kelvin sign.
+	lowered := strings.ToLower(needle)
+
+	index := strings.Index(haystack.String(), lowered)
+	if index < 0 {
+		return h
+	}
+
+	end := index + len(lowered)
+	h.Start = starts[index]
+	h.End = ends[end-1]
+
+	switch {
+	case index == 0 && end == len(starts):
+		h.Quality = MatchExact
+	case index == 0:
+		h.Quality = MatchPrefix
+	default:
+		h.Quality = MatchContains
+	}
+
+	return h
+}
+
+// Matched returns the highlighted part of the matched value.
+func (h Highlight) Matched() string {
+	return h.Value[h.Start:h.End]
+}
+
+// Mark wraps the highlighted part of the matched value in the provided markers, e.g. "<em>" and "</em>".
+func (h Highlight) Mark(before, after string) string {
+	if h.Start == h.End {
+		return h.Value
+	}
+
+	return h.Value[:h.Start] + before + h.Matched() + after + h.Value[h.End:]
+}
+
+func isNotAlphanumeric(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// normalizeSearchValue trims the value, and normalizes IBANs.
+func normalizeSearchValue(field CustomerSearchField, value string) string {
+	value = strings.TrimSpace(value)
+
+	if field == SearchByIBAN {
+		value = iban.Normalize(value)
+	}
+
+	return value
+}
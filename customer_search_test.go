@@ -0,0 +1,158 @@
+package tripica
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   CustomerSearchQuery
+		matched string
+		start   int
+		end     int
+		quality MatchQuality
+		marked  string
+	}{
+		{
+			name:    "contained, ignoring case",
+			query:   CustomerSearchQuery{Field: SearchByEmail, Value: "example"},
+			matched: "max@EXAMPLE.com",
+			start:   4, end: 11, quality: MatchContains,
+			marked: "max@<em>EXAMPLE</em>.com",
+		},
+		{
+			name:    "exact",
+			query:   CustomerSearchQuery{Field: SearchByEmail, Value: " MAX@example.com "},
+			matched: "max@example.com",
+			start:   0, end: 15, quality: MatchExact,
+			marked: "<em>max@example.com</em>",
+		},
+		{
+			name:    "prefix with multi-byte runes",
+			query:   CustomerSearchQuery{Field: SearchByFamilyName, Value: "mül"},
+			matched: "Müller",
+			start:   0, end: 4, quality: MatchPrefix,
+			marked: "<em>Mül</em>ler",
+		},
+		{
+			name:    "IBAN spaces are ignored",
+			query:   CustomerSearchQuery{Field: SearchByIBAN, Value: "de89 3704"},
+			matched: "DE89 3704 0044 0532 0130 00",
+			start:   0, end: 9, quality: MatchPrefix,
+			marked: "<em>DE89 3704</em> 0044 0532 0130 00",
+		},
+		{
+			name:    "needle shrinking when lowercased",
+			query:   CustomerSearchQuery{Field: SearchByFamilyName, Value: "\u1e9e"},
+			matched: "Straße",
+			start:   4, end: 6, quality: MatchContains,
+			marked: "Stra<em>ß</em>e",
+		},
+		{
+			name:    "Kelvin sign in the needle",
+			query:   CustomerSearchQuery{Field: SearchByFamilyName, Value: "\u212aoh"},
+			matched: "Kohl",
+			start:   0, end: 3, quality: MatchPrefix,
+			marked: "<em>Koh</em>l",
+		},
+		{
+			name:    "Kelvin sign in the matched value",
+			query:   CustomerSearchQuery{Field: SearchByFamilyName, Value: "kohl"},
+			matched: "\u212aohl",
+			start:   0, end: 6, quality: MatchExact,
+			marked: "<em>\u212aohl</em>",
+		},
+		{
+			name:    "not found",
+			query:   CustomerSearchQuery{Field: SearchByPostcode, Value: "10115"},
+			matched: "20095",
+			marked:  "20095",
+		},
+		{
+			name:    "empty needle",
+			query:   CustomerSearchQuery{Field: SearchByPostcode, Value: " "},
+			matched: "20095",
+			marked:  "20095",
+		},
+		{
+			name:   "empty matched value",
+			query:  CustomerSearchQuery{Field: SearchByPostcode, Value: "10115"},
+			marked: "",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			h := highlight(tt.query, tt.matched)
+
+			assert.Equal(tt.matched, h.Value)
+			assert.Equal(tt.start, h.Start)
+			assert.Equal(tt.end, h.End)
+			assert.Equal(tt.quality, h.Quality)
+			assert.Equal(tt.marked, h.Mark("<em>", "</em>"))
+		})
+	}
+}
+
+func TestRankSearchHits(t *testing.T) {
+	query := CustomerSearchQuery{Field: SearchByFamilyName, Value: "muster"}
+	hit := func(ouid string, score float64, matched string) *customerSearchHit {
+		return &customerSearchHit{Customer: &Customer{OUID: ouid}, Score: score, MatchedValue: matched}
+	}
+
+	tests := []struct {
+		name  string
+		hits  []*customerSearchHit
+		ouids []string
+	}{
+		{
+			name:  "higher scores first",
+			hits:  []*customerSearchHit{hit("1", 1, "Muster"), hit("2", 3, "Mustermann"), hit("3", 2, "Muster")},
+			ouids: []string{"2", "3", "1"},
+		},
+		{
+			name: "better matches first on equal scores",
+			hits: []*customerSearchHit{
+				hit("1", 1, "Obermuster"), hit("2", 1, "Mustermann"), hit("3", 1, "muster"), hit("4", 1, "Schmidt"),
+			},
+			ouids: []string{"3", "2", "1", "4"},
+		},
+		{
+			name:  "equal hits keep their order",
+			hits:  []*customerSearchHit{hit("1", 1, "Muster"), hit("2", 1, "Muster")},
+			ouids: []string{"1", "2"},
+		},
+		{
+			name:  "hits without customers are dropped",
+			hits:  []*customerSearchHit{nil, {Score: 5}, hit("1", 1, "Muster")},
+			ouids: []string{"1"},
+		},
+		{
+			name:  "no hits",
+			ouids: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			results := rankSearchHits(query, tt.hits)
+
+			ouids := make([]string, 0, len(results))
+			for _, r := range results {
+				ouids = append(ouids, r.Customer.OUID)
+				assert.Equal(t, SearchByFamilyName, r.Field)
+			}
+
+			assert.Equal(t, tt.ouids, ouids)
+		})
+	}
+}
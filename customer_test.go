@@ -0,0 +1,224 @@
+package tripica
+
+import (
+	"context"
+	goerrors "errors"
+	"io"
+	gohttp "net/http"
+	"testing"
+	"tripica-client/http/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	customerCreatePath = "/api/private/v1/agent/customer"
+	customerC1Path     = "/api/private/v1/agent/customer/C1"
+)
+
+// customerServer answers every mutation of a customer with the configured status code and body,
+// recording the last request.
+type customerServer struct {
+	status int
+	body   string
+	method string
+	path   string
+	sent   string
+}
+
+func (s *customerServer) ServeHTTP(w gohttp.ResponseWriter, r *gohttp.Request) {
+	raw, _ := io.ReadAll(r.Body)
+	s.method, s.path, s.sent = r.Method, r.URL.Path, string(raw)
+
+	w.WriteHeader(s.status)
+	_, _ = w.Write([]byte(s.body))
+}
+
+func TestCustomerAPI_Mutations(t *testing.T) {
+	name := "Max"
+
+	mutations := []struct {
+		name      string
+		method    string
+		path      string
+		sent      string
+		versioned bool
+		send      func(c *Client) (*Customer, error)
+	}{
+		{
+			name:   "create",
+			method: gohttp.MethodPost,
+			path:   customerCreatePath,
+			sent:   `{"name":"Max","partyRef":{"partyOuid":"P1"}}`,
+			send: func(c *Client) (*Customer, error) {
+				return c.CreateCustomer(context.Background(), &CustomerCreateRequest{
+					Name:     "Max",
+					PartyRef: PartyRef{PartyOUID: "P1"},
+				})
+			},
+		},
+		{
+			name:      "update",
+			method:    gohttp.MethodPut,
+			path:      customerC1Path,
+			sent:      `{"version":3,"name":"Max","partyRef":{"partyOuid":"P1"},"status":"ACTIVE"}`,
+			versioned: true,
+			send: func(c *Client) (*Customer, error) {
+				return c.UpdateCustomer(context.Background(), "C1", &CustomerUpdateRequest{
+					Version:  3,
+					Name:     "Max",
+					PartyRef: PartyRef{PartyOUID: "P1"},
+					Status:   CustomerStatusActive,
+				})
+			},
+		},
+		{
+			name:      "patch",
+			method:    gohttp.MethodPatch,
+			path:      customerC1Path,
+			sent:      `{"version":3,"name":"Max"}`,
+			versioned: true,
+			send: func(c *Client) (*Customer, error) {
+				return c.PatchCustomer(context.Background(), "C1", &CustomerPatch{Version: 3, Name: &name})
+			},
+		},
+		{
+			name:      "deactivate",
+			method:    gohttp.MethodPatch,
+			path:      customerC1Path,
+			sent:      `{"version":3,"status":"INACTIVE"}`,
+			versioned: true,
+			send: func(c *Client) (*Customer, error) {
+				return c.DeactivateCustomer(context.Background(), "C1", 3)
+			},
+		},
+	}
+
+	for _, tt := range mutations {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			t.Run("success", func(t *testing.T) {
+				srv := &customerServer{
+					status: gohttp.StatusOK,
+					body:   `{"ouid":"C1","name":"Max","version":4,"partyRef":{"partyOuid":"P1"}}`,
+				}
+
+				customer, err := tt.send(newTestClient(t, srv, nil))
+
+				require.NoError(t, err)
+				assert.Equal(tt.method, srv.method)
+				assert.Equal(tt.path, srv.path)
+				assert.JSONEq(tt.sent, srv.sent)
+				assert.Equal("C1", customer.OUID)
+				assert.Equal(4, customer.Version)
+			})
+
+			t.Run("field errors", func(t *testing.T) {
+				srv := &customerServer{
+					status: gohttp.StatusBadRequest,
+					body:   `{"code":"INVALID","message":"invalid customer","errors":[{"field":"name","code":"tooLong"}]}`,
+				}
+
+				customer, err := tt.send(newTestClient(t, srv, nil))
+
+				assert.Nil(customer)
+
+				var validationErr *ValidationError
+				require.True(t, goerrors.As(err, &validationErr))
+				assert.Equal(gohttp.StatusBadRequest, validationErr.StatusCode)
+				assert.Equal("INVALID", validationErr.Code)
+				assert.Equal("tooLong", validationErr.Field("name").Code)
+			})
+
+			t.Run("conflict", func(t *testing.T) {
+				srv := &customerServer{status: gohttp.StatusConflict, body: "conflict"}
+
+				customer, err := tt.send(newTestClient(t, srv, nil))
+
+				assert.Nil(customer)
+
+				var conflictErr *VersionConflictError
+				assert.Equal(tt.versioned, goerrors.As(err, &conflictErr))
+
+				if tt.versioned {
+					assert.Equal(VersionConflictError{
+						Resource:   "customer",
+						OUID:       "C1",
+						Version:    3,
+						StatusCode: gohttp.StatusConflict,
+					}, *conflictErr)
+
+					return
+				}
+
+				var httpErr *errors.HTTPError
+				require.True(t, goerrors.As(err, &httpErr))
+				assert.Equal(gohttp.StatusConflict, httpErr.StatusCode)
+				assert.Equal("conflict", httpErr.Body)
+			})
+		})
+	}
+}
+
+func TestNewValidationError(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want ValidationError
+	}{
+		{
+			name: "field errors",
+			body: `{"code":"INVALID","message":"invalid","errors":[{"field":"name","code":"required","message":"missing"}]}`,
+			want: ValidationError{
+				StatusCode: gohttp.StatusUnprocessableEntity,
+				Code:       "INVALID",
+				Message:    "invalid",
+				Fields:     []FieldError{{Field: "name", Code: "required", Message: "missing"}},
+			},
+		},
+		{
+			name: "other JSON is kept as message",
+			body: `{"error":"invalid"}`,
+			want: ValidationError{StatusCode: gohttp.StatusUnprocessableEntity, Message: `{"error":"invalid"}`},
+		},
+		{
+			name: "text is kept as message",
+			body: " invalid customer\n",
+			want: ValidationError{StatusCode: gohttp.StatusUnprocessableEntity, Message: "invalid customer"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &customerServer{status: gohttp.StatusUnprocessableEntity, body: tt.body}
+
+			_, err := newTestClient(t, srv, nil).PatchCustomer(context.Background(), "C1", &CustomerPatch{})
+
+			var validationErr *ValidationError
+			require.True(t, goerrors.As(err, &validationErr))
+			assert.Equal(t, tt.want, *validationErr)
+		})
+	}
+}
+
+func TestCustomerAPI_Mutations_Validation(t *testing.T) {
+	srv := &customerServer{status: gohttp.StatusOK}
+
+	customer, err := newTestClient(t, srv, nil).CreateCustomer(context.Background(), &CustomerCreateRequest{
+		Status: "DELETED",
+	})
+
+	assert.Nil(t, customer)
+
+	var validationErr *ValidationError
+	require.True(t, goerrors.As(err, &validationErr))
+	assert.Zero(t, validationErr.StatusCode)
+	assert.Len(t, validationErr.Fields, 3)
+	assert.Empty(t, srv.method, "invalid requests aren't sent")
+}
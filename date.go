@@ -1,26 +1,131 @@
 package tripica
 
 import (
+	"bytes"
+	"fmt"
 	"strconv"
 	"strings"
 	"time"
+
+	// Embeds the time zone database, so that Europe/Berlin is available on every system.
+	_ "time/tzdata"
 )
 
+// dateLayouts lists the accepted ISO-8601 layouts of string dates.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// Berlin is the Europe/Berlin location, used by triPica for calendar dates.
+var Berlin = mustLoadLocation("Europe/Berlin")
+
 // Date represents a triPica date formatted in timestamp in milliseconds.
+// It is decoded from timestamps in milliseconds, numeric strings and ISO-8601 strings,
+// while null decodes into the zero Date. It is always encoded as a timestamp in milliseconds,
+// with the zero Date being encoded as null. Timestamps in seconds aren't accepted, since they can't be told
+// apart from milliseconds by their magnitude, and all triPica timestamps are in milliseconds.
 type Date struct {
 	time.Time
 }
 
+// NewDate returns a Date for the provided time.
+func NewDate(t time.Time) Date {
+	return Date{Time: t}
+}
+
+// DateFromMillis returns a Date for the provided timestamp in milliseconds.
+func DateFromMillis(millis int64) Date {
+	return Date{Time: time.Unix(0, millis*int64(time.Millisecond))}
+}
+
+// Millis returns the date as a timestamp in milliseconds.
+func (d Date) Millis() int64 {
+	return d.UnixNano() / int64(time.Millisecond)
+}
+
+// InBerlin returns the date in the Europe/Berlin location.
+func (d Date) InBerlin() time.Time {
+	return d.In(Berlin)
+}
+
+// BerlinDay returns the start of the date's calendar day in Europe/Berlin.
+func (d Date) BerlinDay() time.Time {
+	t := d.InBerlin()
+
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, Berlin)
+}
+
+// SameBerlinDay determines whether both dates fall on the same calendar day in Europe/Berlin.
+func (d Date) SameBerlinDay(other Date) bool {
+	return d.BerlinDay().Equal(other.BerlinDay())
+}
+
+// FormatBerlin formats the date in the Europe/Berlin location, e.g. FormatBerlin("02.01.2006").
+func (d Date) FormatBerlin(layout string) string {
+	return d.InBerlin().Format(layout)
+}
+
+// MarshalJSON converts the date to a timestamp in milliseconds, or null for the zero Date.
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return []byte(strconv.FormatInt(d.Millis(), 10)), nil
+}
+
 // UnmarshalJSON converts a timestamp in milliseconds to time.Time.
+// Numeric strings, ISO-8601 strings and null are accepted as well.
 func (d *Date) UnmarshalJSON(b []byte) error {
-	s := strings.Trim(string(b), `"`)
-
-	timestamp, err := strconv.ParseInt(s, 10, 64)
+	t, err := parseDate(b)
 	if err != nil {
 		return err
 	}
 
-	d.Time = time.Unix(0, timestamp*int64(time.Millisecond))
+	d.Time = t
 
 	return nil
 }
+
+// parseDate parses a JSON timestamp in milliseconds, a numeric string of it, an ISO-8601 string or null.
+func parseDate(b []byte) (time.Time, error) {
+	b = bytes.TrimSpace(b)
+
+	if bytes.Equal(b, []byte("null")) {
+		return time.Time{}, nil
+	}
+
+	s := strings.Trim(string(b), `"`)
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	if timestamp, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return DateFromMillis(timestamp).Time, nil
+	}
+
+	for _, layout := range dateLayouts {
+		loc := time.UTC
+		if layout != time.RFC3339Nano {
+			// Dates without a zone are triPica calendar dates.
+			loc = Berlin
+		}
+
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid triPica date %s", b)
+}
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+
+	return loc
+}
@@ -0,0 +1,98 @@
+package tripica
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDate_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		millis int64
+		utc    string
+	}{
+		{"epoch", 0, "1970-01-01T00:00:00Z"},
+		{"before epoch", -1, "1969-12-31T23:59:59.999Z"},
+		{"1967", -94694400000, "1967-01-01T00:00:00Z"},
+		{"1971", 31536000123, "1971-01-01T00:00:00.123Z"},
+		{"below 1e11", 99999999999, "1973-03-03T09:46:39.999Z"},
+		{"2020", 1600000000000, "2020-09-13T12:26:40Z"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			var d Date
+			require.NoError(json.Unmarshal([]byte(strconv.FormatInt(tt.millis, 10)), &d))
+			assert.Equal(tt.utc, d.UTC().Format(time.RFC3339Nano))
+			assert.Equal(tt.millis, d.Millis())
+
+			encoded, err := json.Marshal(d)
+			require.NoError(err)
+			assert.Equal(strconv.FormatInt(tt.millis, 10), string(encoded))
+
+			var quoted Date
+			require.NoError(json.Unmarshal([]byte(`"`+strconv.FormatInt(tt.millis, 10)+`"`), &quoted))
+			assert.True(d.Equal(quoted.Time))
+		})
+	}
+}
+
+func TestDate_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		data string
+		utc  string
+		err  bool
+	}{
+		{data: `null`},
+		{data: `""`},
+		{data: `"2020-09-13T14:26:40+02:00"`, utc: "2020-09-13T12:26:40Z"},
+		{data: `"2020-09-13T14:26:40.5"`, utc: "2020-09-13T12:26:40.5Z"},
+		{data: `"2020-01-31"`, utc: "2020-01-30T23:00:00Z"},
+		{data: `"31.01.2020"`, err: true},
+		{data: `true`, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			d := DateFromMillis(1)
+
+			err := json.Unmarshal([]byte(tt.data), &d)
+			if tt.err {
+				assert.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			if tt.utc == "" {
+				assert.True(t, d.IsZero())
+
+				return
+			}
+
+			assert.Equal(t, tt.utc, d.UTC().Format(time.RFC3339Nano))
+		})
+	}
+}
+
+func TestDate_MarshalJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	encoded, err := json.Marshal(struct {
+		Start Date  `json:"start"`
+		End   *Date `json:"end,omitempty"`
+	}{})
+	assert.NoError(err)
+	assert.Equal(`{"start":null}`, string(encoded))
+}
@@ -0,0 +1,257 @@
+package tripica
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// versionPlaceholder is replaced by the selected API version of the area in base paths.
+const versionPlaceholder = "{version}"
+
+// DefaultAPIVersion is the API version used for areas without a selected version.
+const DefaultAPIVersion = "v1"
+
+// Possible API areas, each one sharing a base path and API version.
+const (
+	AreaBilling       APIArea = "billing"
+	AreaCustomer      APIArea = "customer"
+	AreaIndividual    APIArea = "individual"
+	AreaLoginAgent    APIArea = "loginAgent"
+	AreaLoginCustomer APIArea = "loginCustomer"
+	AreaLoginPrivate  APIArea = "loginPrivate"
+	AreaNetworkEntity APIArea = "networkEntity"
+	AreaNotify        APIArea = "notify"
+	AreaOrganization  APIArea = "organization"
+	AreaProduct       APIArea = "product"
+)
+
+// Possible endpoints, whose path templates can be overridden.
+const (
+	EndpointBillingAccountByMBA                   Endpoint = "billing.billingAccountByMBA"
+	EndpointBillingAccountsByCustomer             Endpoint = "billing.billingAccountsByCustomer"
+	EndpointDueBillingAccountBalancesByCustomer   Endpoint = "billing.dueBillingAccountBalancesByCustomer"
+	EndpointAppliedBillingCharges                 Endpoint = "billing.appliedBillingCharges"
+	EndpointSettlementNoteAdvicesByBillingAccount Endpoint = "billing.settlementNoteAdvicesByBillingAccount"
+	EndpointCustomerCreate                        Endpoint = "customer.create"
+	EndpointCustomerByOUID                        Endpoint = "customer.byOUID"
+	EndpointCustomerByName                        Endpoint = "customer.byName"
+	EndpointCustomerSearch                        Endpoint = "customer.search"
+	EndpointCustomerPaymentMeans                  Endpoint = "customer.paymentMeans"
+	EndpointCustomerPaymentMean                   Endpoint = "customer.paymentMean"
+	EndpointIndividualByPartyOUID                 Endpoint = "individual.byPartyOUID"
+	EndpointIndividualContactMediums              Endpoint = "individual.contactMediums"
+	EndpointIndividualContactMedium               Endpoint = "individual.contactMedium"
+	EndpointLoginByCustomerOUID                   Endpoint = "loginAgent.byCustomerOUID"
+	EndpointLoginGenerateJWT                      Endpoint = "loginCustomer.generateJWT"
+	EndpointLoginInfo                             Endpoint = "loginPrivate.info"
+	EndpointNetworkEntityBySubscriptionOUID       Endpoint = "networkEntity.bySubscriptionOUID"
+	EndpointNotifySendNotification                Endpoint = "notify.sendNotification"
+	EndpointNotifySendTermination                 Endpoint = "notify.sendTermination"
+	EndpointOrganizationByPartyOUID               Endpoint = "organization.byPartyOUID"
+	EndpointProductsByCustomer                    Endpoint = "product.byCustomer"
+	EndpointProductOrdersByCustomer               Endpoint = "product.productOrdersByCustomer"
+)
+
+var (
+	errUnknownArea        = errors.New("unknown API area")
+	errUnknownEndpoint    = errors.New("unknown endpoint")
+	errInvalidVersion     = errors.New("invalid API version")
+	errInvalidBasePath    = errors.New("invalid base path")
+	errInvalidPlaceholder = errors.New("invalid path template placeholders")
+
+	// defaultBasePaths are the base path templates of each area.
+	defaultBasePaths = map[APIArea]string{
+		AreaBilling:       billingBasePath,
+		AreaCustomer:      customerBasePath,
+		AreaIndividual:    individualBasePath,
+		AreaLoginAgent:    loginBasePathAgent,
+		AreaLoginCustomer: loginBasePathCustomer,
+		AreaLoginPrivate:  loginBasePathPrivateCustomer,
+		AreaNetworkEntity: networkEntityBasePath,
+		AreaNotify:        notifyBasePath,
+		AreaOrganization:  organizationBasePath,
+		AreaProduct:       productBasePath,
+	}
+
+	// defaultEndpoints are the area and path template, relative to the area's base path, of each endpoint.
+	defaultEndpoints = map[Endpoint]endpointTemplate{
+		EndpointBillingAccountByMBA:                   {AreaBilling, billingPathGetBillingAccountByMBA},
+		EndpointBillingAccountsByCustomer:             {AreaBilling, billingPathGetBillingAccountsByCustomer},
+		EndpointDueBillingAccountBalancesByCustomer:   {AreaBilling, billingPathGetDueBillingAccountBalancesByCustomer},
+		EndpointAppliedBillingCharges:                 {AreaBilling, billingPathGetAppliedBillingCharges},
+		EndpointSettlementNoteAdvicesByBillingAccount: {AreaBilling, billingPathGetListOfSettlementNodeAdviceByAccount},
+		EndpointCustomerCreate:                        {AreaCustomer, customerPathCreate},
+		EndpointCustomerByOUID:                        {AreaCustomer, customerPathGetByOUID},
+		EndpointCustomerByName:                        {AreaCustomer, customerPathGetByName},
+		EndpointCustomerSearch:                        {AreaCustomer, customerPathSearch},
+		EndpointCustomerPaymentMeans:                  {AreaCustomer, customerPathPaymentMeans},
+		EndpointCustomerPaymentMean:                   {AreaCustomer, customerPathPaymentMean},
+		EndpointIndividualByPartyOUID:                 {AreaIndividual, individualPathGetByPartyOUID},
+		EndpointIndividualContactMediums:              {AreaIndividual, individualPathContactMediums},
+		EndpointIndividualContactMedium:               {AreaIndividual, individualPathContactMedium},
+		EndpointLoginByCustomerOUID:                   {AreaLoginAgent, loginPathGetByCustomerOUID},
+		EndpointLoginGenerateJWT:                      {AreaLoginCustomer, loginPathGenerateJWT},
+		EndpointLoginInfo:                             {AreaLoginPrivate, ""},
+		EndpointNetworkEntityBySubscriptionOUID: {
+			AreaNetworkEntity, networkEntityPathGetNetworkEntityBySubscriptionOuid,
+		},
+		EndpointNotifySendNotification:  {AreaNotify, notifyPathSendNotification},
+		EndpointNotifySendTermination:   {AreaNotify, notifyPathSendTermination},
+		EndpointOrganizationByPartyOUID: {AreaOrganization, organizationPathGetByPartyOUID},
+		EndpointProductsByCustomer:      {AreaProduct, productPathGetByCustomerOuid},
+		EndpointProductOrdersByCustomer: {AreaProduct, productPathGetProductOrdersByCustomerOuid},
+	}
+)
+
+type (
+	// APIArea represents a group of triPica endpoints sharing a base path and API version.
+	APIArea string
+
+	// Endpoint identifies a triPica endpoint.
+	Endpoint string
+
+	// Endpoints overrides the paths of triPica endpoints, e.g. for environments behind a gateway.
+	// Base paths may contain the {version} placeholder, which is replaced by the version selected for the area.
+	// Path templates are relative to the base path of their area, and need to contain the same number
+	// of %s placeholders as the default template, e.g. "/customers/%s" for EndpointCustomerByOUID.
+	// Areas and endpoints which aren't overridden keep their defaults.
+	Endpoints struct {
+		Versions  map[APIArea]string
+		BasePaths map[APIArea]string
+		Paths     map[Endpoint]string
+	}
+
+	endpointTemplate struct {
+		area APIArea
+		path string
+	}
+
+	// endpointRegistry holds the resolved paths of all endpoints, without the host.
+	endpointRegistry struct {
+		paths map[Endpoint]string
+	}
+)
+
+// Validate checks that all overridden areas and endpoints exist, and that their templates have
+// the right placeholders. All errors are reported at once.
+func (e *Endpoints) Validate() error {
+	_, err := newEndpointRegistry(e)
+
+	return err
+}
+
+// newEndpointRegistry resolves the paths of all endpoints, applying the overrides of e, which may be nil.
+func newEndpointRegistry(e *Endpoints) (*endpointRegistry, error) {
+	if e == nil {
+		e = &Endpoints{}
+	}
+
+	var messages []string
+
+	for _, area := range sortedKeys(e.Versions) {
+		if _, ok := defaultBasePaths[area]; !ok {
+			messages = append(messages, fmt.Sprintf("%s: %s", errUnknownArea, area))
+		} else if v := e.Versions[area]; v == "" || strings.ContainsAny(v, "/%{} ") {
+			messages = append(messages, fmt.Sprintf("%s of %s: %q", errInvalidVersion, area, v))
+		}
+	}
+
+	for _, area := range sortedKeys(e.BasePaths) {
+		if _, ok := defaultBasePaths[area]; !ok {
+			messages = append(messages, fmt.Sprintf("%s: %s", errUnknownArea, area))
+		} else if err := validateBasePath(e.BasePaths[area]); err != nil {
+			messages = append(messages, fmt.Sprintf("%s of %s: %s", errInvalidBasePath, area, err))
+		}
+	}
+
+	for _, endpoint := range sortedKeys(e.Paths) {
+		template, ok := defaultEndpoints[endpoint]
+		if !ok {
+			messages = append(messages, fmt.Sprintf("%s: %s", errUnknownEndpoint, endpoint))
+
+			continue
+		}
+
+		if err := validatePath(e.Paths[endpoint], strings.Count(template.path, "%s")); err != nil {
+			messages = append(messages, fmt.Sprintf("%s of %s: %s", errInvalidPlaceholder, endpoint, err))
+		}
+	}
+
+	if len(messages) > 0 {
+		return nil, NewTriPicaError(fmt.Errorf("invalid endpoints: %s", strings.Join(messages, "; ")))
+	}
+
+	registry := &endpointRegistry{paths: make(map[Endpoint]string, len(defaultEndpoints))}
+
+	for endpoint, template := range defaultEndpoints {
+		basePath, ok := e.BasePaths[template.area]
+		if !ok {
+			basePath = defaultBasePaths[template.area]
+		}
+
+		version, ok := e.Versions[template.area]
+		if !ok {
+			version = DefaultAPIVersion
+		}
+
+		path, ok := e.Paths[endpoint]
+		if !ok {
+			path = template.path
+		}
+
+		registry.paths[endpoint] = strings.ReplaceAll(basePath, versionPlaceholder, version) + path
+	}
+
+	return registry, nil
+}
+
+// path returns the path template of the endpoint, which needs to be formatted with its identifiers.
+// It also identifies the endpoint in schema drift events, as it doesn't contain any identifiers.
+func (r *endpointRegistry) path(endpoint Endpoint) string {
+	return r.paths[endpoint]
+}
+
+func validateBasePath(basePath string) error {
+	if basePath != "" && !strings.HasPrefix(basePath, "/") {
+		return fmt.Errorf("%q doesn't start with /", basePath)
+	}
+
+	if strings.Contains(basePath, "%") {
+		return fmt.Errorf("%q can't contain %% placeholders", basePath)
+	}
+
+	if rest := strings.ReplaceAll(basePath, versionPlaceholder, ""); strings.ContainsAny(rest, "{}") {
+		return fmt.Errorf("%q contains placeholders other than %s", basePath, versionPlaceholder)
+	}
+
+	return nil
+}
+
+func validatePath(path string, placeholders int) error {
+	if path != "" && !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("%q doesn't start with /", path)
+	}
+
+	if n := strings.Count(path, "%s"); n != placeholders {
+		return fmt.Errorf("%q has %d %%s placeholders instead of %d", path, n, placeholders)
+	}
+
+	if strings.Count(path, "%") != placeholders {
+		return fmt.Errorf("%q contains placeholders other than %%s", path)
+	}
+
+	return nil
+}
+
+func sortedKeys[K ~string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	return keys
+}
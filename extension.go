@@ -0,0 +1,219 @@
+package tripica
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// knownFieldsCache caches the JSON field names declared by model types.
+var knownFieldsCache sync.Map
+
+// Extensions holds the JSON properties returned by triPica which are not declared by a model,
+// allowing new attributes to be read before the library supports them.
+// Extensions are written back when the model is marshaled.
+type Extensions map[string]json.RawMessage
+
+// Has determines whether the property is present.
+func (e Extensions) Has(key string) bool {
+	_, ok := e[key]
+
+	return ok
+}
+
+// Keys returns the names of all properties, sorted alphabetically.
+func (e Extensions) Keys() []string {
+	keys := make([]string, 0, len(e))
+	for key := range e {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Decode decodes the property into v.
+func (e Extensions) Decode(key string, v interface{}) error {
+	raw, ok := e[key]
+	if !ok {
+		return NewTriPicaError(fmt.Errorf("extension %s not found", key))
+	}
+
+	if err := json.Unmarshal(raw, v); err != nil {
+		return NewTriPicaError(fmt.Errorf("couldn't decode extension %s: %w", key, err))
+	}
+
+	return nil
+}
+
+// String returns the property as a string. The second value reports whether it is present and a string.
+func (e Extensions) String(key string) (string, bool) {
+	var v string
+
+	return v, e.Decode(key, &v) == nil
+}
+
+// Int returns the property as an integer. The second value reports whether it is present and an integer.
+func (e Extensions) Int(key string) (int64, bool) {
+	var v int64
+
+	return v, e.Decode(key, &v) == nil
+}
+
+// Float returns the property as a float. The second value reports whether it is present and a number.
+func (e Extensions) Float(key string) (float64, bool) {
+	var v float64
+
+	return v, e.Decode(key, &v) == nil
+}
+
+// Bool returns the property as a boolean. The second value reports whether it is present and a boolean.
+func (e Extensions) Bool(key string) (bool, bool) {
+	var v bool
+
+	return v, e.Decode(key, &v) == nil
+}
+
+// Date returns the property as a Date. The second value reports whether it is present and a valid date.
+func (e Extensions) Date(key string) (Date, bool) {
+	var v Date
+
+	return v, e.Decode(key, &v) == nil
+}
+
+// unmarshalExtended decodes the data into the model v, storing undeclared properties into ext.
+// The model needs to be of a type without its own UnmarshalJSON method, which is usually a local type
+// definition based on the actual model.
+func unmarshalExtended(data []byte, v interface{}, ext *Extensions) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	var properties map[string]json.RawMessage
+	if err := json.Unmarshal(data, &properties); err != nil || properties == nil {
+		// The data is not an object, e.g. it is null.
+		return nil //nolint: nilerr
+	}
+
+	known := knownFields(reflect.TypeOf(v))
+	*ext = nil
+
+	for key, value := range properties {
+		if known[strings.ToLower(key)] {
+			continue
+		}
+
+		if *ext == nil {
+			*ext = Extensions{}
+		}
+
+		(*ext)[key] = value
+	}
+
+	return nil
+}
+
+// marshalExtended encodes the model v, adding the extension properties which are not declared by the model.
+func marshalExtended(v interface{}, ext Extensions) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(ext) == 0 {
+		return data, err
+	}
+
+	known := knownFields(reflect.TypeOf(v))
+
+	data = bytes.TrimSpace(data)
+	empty := bytes.Equal(data, []byte("{}"))
+
+	var buf bytes.Buffer
+
+	// Strip the closing brace, so that the extensions can be appended to the object.
+	buf.Write(data[:len(data)-1])
+
+	for _, key := range ext.Keys() {
+		if known[strings.ToLower(key)] {
+			continue
+		}
+
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if !empty {
+			buf.WriteByte(',')
+		}
+
+		empty = false
+
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(ext[key])
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// knownFields returns the lower cased JSON names of the fields declared by the struct type.
+func knownFields(t reflect.Type) map[string]bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if fields, ok := knownFieldsCache.Load(t); ok {
+		return fields.(map[string]bool)
+	}
+
+	fields := map[string]bool{}
+	collectKnownFields(t, fields)
+	knownFieldsCache.Store(t, fields)
+
+	return fields
+}
+
+func collectKnownFields(t reflect.Type, fields map[string]bool) {
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+
+		if tag == "-" {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Struct {
+				collectKnownFields(ft, fields)
+
+				continue
+			}
+		}
+
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		fields[strings.ToLower(name)] = true
+	}
+}
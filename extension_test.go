@@ -0,0 +1,84 @@
+package tripica
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtensions_RoundTrip(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	t.Run("undeclared properties are retained and written back", func(t *testing.T) {
+		data := `{"ouid":"1","type":"PAYER","targetBillingAccountOuid":"2",` +
+			`"priority":3,"label":"main","shared":true,"nested":{"a":[1,2]}}`
+
+		var relationship BillingAccountRelationship
+		require.NoError(json.Unmarshal([]byte(data), &relationship))
+
+		assert.Equal("1", relationship.OUID)
+		assert.Equal([]string{"label", "nested", "priority", "shared"}, relationship.Extensions.Keys())
+		assert.False(relationship.Extensions.Has("ouid"))
+
+		priority, ok := relationship.Extensions.Int("priority")
+		assert.True(ok)
+		assert.Equal(int64(3), priority)
+
+		label, ok := relationship.Extensions.String("label")
+		assert.True(ok)
+		assert.Equal("main", label)
+
+		_, ok = relationship.Extensions.String("priority")
+		assert.False(ok)
+
+		var nested struct{ A []int }
+		require.NoError(relationship.Extensions.Decode("nested", &nested))
+		assert.Equal([]int{1, 2}, nested.A)
+
+		encoded, err := json.Marshal(relationship)
+		require.NoError(err)
+		assert.JSONEq(data, string(encoded))
+	})
+
+	t.Run("extensions don't override declared fields", func(t *testing.T) {
+		relationship := BillingAccountRelationship{
+			OUID:       "1",
+			Extensions: Extensions{"OUID": json.RawMessage(`"2"`), "label": json.RawMessage(`"main"`)},
+		}
+
+		encoded, err := json.Marshal(relationship)
+		require.NoError(err)
+		assert.JSONEq(`{"ouid":"1","type":"","targetBillingAccountOuid":"","label":"main"}`, string(encoded))
+	})
+
+	t.Run("nested models retain their own extensions", func(t *testing.T) {
+		data := `{"ouid":"1","name":"MBA","customerOuid":"2","origin":"shop",` +
+			`"billingAccountRelationships":[{"ouid":"3","weight":1}]}`
+
+		var account BillingAccount
+		require.NoError(json.Unmarshal([]byte(data), &account))
+
+		assert.Equal([]string{"origin"}, account.Extensions.Keys())
+		require.Len(account.BillingAccountRelationships, 1)
+		assert.Equal([]string{"weight"}, account.BillingAccountRelationships[0].Extensions.Keys())
+	})
+
+	t.Run("models without undeclared properties have no extensions", func(t *testing.T) {
+		var relationship BillingAccountRelationship
+		require.NoError(json.Unmarshal([]byte(`{"ouid":"1"}`), &relationship))
+		assert.Nil(relationship.Extensions)
+
+		require.NoError(json.Unmarshal([]byte(`null`), &relationship))
+
+		_, err := json.Marshal(relationship)
+		assert.NoError(err)
+	})
+
+	t.Run("missing extensions can't be decoded", func(t *testing.T) {
+		var v string
+		assert.Error(Extensions{}.Decode("label", &v))
+	})
+}
@@ -0,0 +1,155 @@
+package tripica
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	filterQueryParam = "filters"
+	filterFieldBegin = "begin"
+	filterFieldEnd   = "end"
+	filterFieldState = "status"
+)
+
+var (
+	errFilterEmptyField  = errors.New("filter field name is empty")
+	errFilterEmptyValue  = errors.New("filter value is empty")
+	errFilterNoValues    = errors.New("filter requires at least one value")
+	errFilterInvalidDate = errors.New("filter date range begins after it ends")
+)
+
+// Filter builds the filters of triPica list endpoints.
+// Conditions are chained, and validated once the filter is encoded.
+//
+//	filter := NewFilter().In("transactionIds", "T1", "T2").Status("ACTIVE")
+type Filter struct {
+	conditions map[string]interface{}
+	errs       []error
+}
+
+// NewFilter returns an empty Filter.
+func NewFilter() *Filter {
+	return &Filter{conditions: map[string]interface{}{}}
+}
+
+// Equal requires the field to be equal to the value.
+func (f *Filter) Equal(field, value string) *Filter {
+	if f.validField(field) && f.validValues(field, value) {
+		f.conditions[field] = value
+	}
+
+	return f
+}
+
+// In requires the field to be equal to one of the values.
+func (f *Filter) In(field string, values ...string) *Filter {
+	if f.validField(field) && f.validValues(field, values...) {
+		f.conditions[field] = values
+	}
+
+	return f
+}
+
+// DateRange restricts the results to those valid between begin and end. Zero times leave the range open.
+func (f *Filter) DateRange(begin, end time.Time) *Filter {
+	if !begin.IsZero() && !end.IsZero() && begin.After(end) {
+		f.errs = append(f.errs, fmt.Errorf("%w: %s > %s", errFilterInvalidDate, begin, end))
+
+		return f
+	}
+
+	if !begin.IsZero() {
+		f.conditions[filterFieldBegin] = NewDate(begin).Millis()
+	}
+
+	if !end.IsZero() {
+		f.conditions[filterFieldEnd] = NewDate(end).Millis()
+	}
+
+	return f
+}
+
+// Status restricts the results to those having one of the statuses.
+func (f *Filter) Status(statuses ...string) *Filter {
+	return f.In(filterFieldState, statuses...)
+}
+
+// Validate returns all errors which occurred while building the filter.
+func (f *Filter) Validate() error {
+	if len(f.errs) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(f.errs))
+	for _, err := range f.errs {
+		messages = append(messages, err.Error())
+	}
+
+	return NewTriPicaError(fmt.Errorf("invalid filter: %s", strings.Join(messages, "; ")))
+}
+
+// Encode validates the filter, and encodes it into a query escaped value of the filters query parameter.
+func (f *Filter) Encode() (string, error) {
+	if err := f.Validate(); err != nil {
+		return "", err
+	}
+
+	v, err := json.Marshal(f.conditions)
+	if err != nil {
+		return "", NewTriPicaError(fmt.Errorf("couldn't encode filter: %w", err))
+	}
+
+	return url.QueryEscape(string(v)), nil
+}
+
+func (f *Filter) validField(field string) bool {
+	if strings.TrimSpace(field) == "" {
+		f.errs = append(f.errs, errFilterEmptyField)
+
+		return false
+	}
+
+	return true
+}
+
+func (f *Filter) validValues(field string, values ...string) bool {
+	if len(values) == 0 {
+		f.errs = append(f.errs, fmt.Errorf("%w: %s", errFilterNoValues, field))
+
+		return false
+	}
+
+	for _, v := range values {
+		if strings.TrimSpace(v) == "" {
+			f.errs = append(f.errs, fmt.Errorf("%w: %s", errFilterEmptyValue, field))
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// withFilter appends the encoded filter to the URL. A nil filter leaves the URL unchanged.
+func withFilter(rawURL string, filter *Filter) (string, error) {
+	if filter == nil || len(filter.conditions) == 0 && len(filter.errs) == 0 {
+		return rawURL, nil
+	}
+
+	f, err := filter.Encode()
+	if err != nil {
+		return "", err
+	}
+
+	separator := "?"
+	if strings.Contains(rawURL, "?") {
+		separator = "&"
+	}
+
+	return rawURL + separator + filterQueryParam + "=" + f, nil
+}
@@ -0,0 +1,218 @@
+package tripica
+
+import (
+	goerrors "errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Encode(t *testing.T) {
+	begin := time.UnixMilli(1600000000000)
+	end := time.UnixMilli(1700000000000)
+
+	tests := []struct {
+		name    string
+		filter  *Filter
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "empty",
+			filter: NewFilter(),
+			want:   `{}`,
+		},
+		{
+			name:   "equal",
+			filter: NewFilter().Equal("name", "Max"),
+			want:   `{"name":"Max"}`,
+		},
+		{
+			name:   "in",
+			filter: NewFilter().In("transactionIds", "T1", "T2"),
+			want:   `{"transactionIds":["T1","T2"]}`,
+		},
+		{
+			name:   "status",
+			filter: NewFilter().Status("ACTIVE", "SUSPENDED"),
+			want:   `{"status":["ACTIVE","SUSPENDED"]}`,
+		},
+		{
+			name:   "date range",
+			filter: NewFilter().DateRange(begin, end),
+			want:   `{"begin":1600000000000,"end":1700000000000}`,
+		},
+		{
+			name:   "open date range",
+			filter: NewFilter().DateRange(time.Time{}, end),
+			want:   `{"end":1700000000000}`,
+		},
+		{
+			name:   "chained conditions",
+			filter: NewFilter().Equal("name", "Max").Status("ACTIVE").DateRange(begin, time.Time{}),
+			want:   `{"begin":1600000000000,"name":"Max","status":["ACTIVE"]}`,
+		},
+		{
+			name:   "special characters",
+			filter: NewFilter().Equal("name", "Max & Erika=\"1\""),
+			want:   `{"name":"Max & Erika=\"1\""}`,
+		},
+		{
+			name:    "empty field",
+			filter:  NewFilter().Equal(" ", "Max"),
+			wantErr: errFilterEmptyField,
+		},
+		{
+			name:    "empty value",
+			filter:  NewFilter().In("transactionIds", "T1", " "),
+			wantErr: errFilterEmptyValue,
+		},
+		{
+			name:    "no values",
+			filter:  NewFilter().Status(),
+			wantErr: errFilterNoValues,
+		},
+		{
+			name:    "date range beginning after its end",
+			filter:  NewFilter().DateRange(end, begin),
+			wantErr: errFilterInvalidDate,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := tt.filter.Encode()
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr.Error())
+				assert.Empty(t, encoded)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			decoded, err := url.QueryUnescape(encoded)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, decoded)
+		})
+	}
+}
+
+func TestFilter_Validate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(NewFilter().Equal("name", "Max").Validate())
+
+	err := NewFilter().Equal("", "Max").In("transactionIds").Validate()
+
+	var tripicaErr *Error
+	require.True(t, goerrors.As(err, &tripicaErr))
+	assert.Contains(err.Error(), errFilterEmptyField.Error())
+	assert.Contains(err.Error(), errFilterNoValues.Error())
+}
+
+func TestWithFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		filter  *Filter
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "nil",
+			url:  "http://tripica/products",
+			want: "http://tripica/products",
+		},
+		{
+			name:   "empty",
+			url:    "http://tripica/products",
+			filter: NewFilter(),
+			want:   "http://tripica/products",
+		},
+		{
+			name:   "first query parameter",
+			url:    "http://tripica/products",
+			filter: NewFilter().Status("ACTIVE"),
+			want:   "http://tripica/products?filters=" + url.QueryEscape(`{"status":["ACTIVE"]}`),
+		},
+		{
+			name:   "additional query parameter",
+			url:    "http://tripica/products?limit=10",
+			filter: NewFilter().Status("ACTIVE"),
+			want:   "http://tripica/products?limit=10&filters=" + url.QueryEscape(`{"status":["ACTIVE"]}`),
+		},
+		{
+			name:    "invalid",
+			url:     "http://tripica/products",
+			filter:  NewFilter().Status(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := withFilter(tt.url, tt.filter)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Empty(t, got)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProductDateFilter_Encode(t *testing.T) {
+	begin, end := 1700000000000, 1600000000000
+
+	tests := []struct {
+		name   string
+		filter *ProductDateFilter
+		want   string
+	}{
+		{
+			name: "nil",
+			want: `null`,
+		},
+		{
+			name:   "empty",
+			filter: &ProductDateFilter{},
+			want:   `{}`,
+		},
+		{
+			name:   "begin after end",
+			filter: &ProductDateFilter{Begin: &begin, End: &end},
+			want:   `{"begin":1700000000000,"end":1600000000000}`,
+		},
+		{
+			name:   "end only",
+			filter: &ProductDateFilter{End: &end},
+			want:   `{"end":1600000000000}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := tt.filter.Encode()
+			require.NoError(t, err)
+
+			decoded, err := url.QueryUnescape(encoded)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, decoded)
+		})
+	}
+}
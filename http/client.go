@@ -1,8 +1,13 @@
 package http
 
 import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
 	"net/http"
 	"time"
+	"tripica-client/clock"
 	"tripica-client/log"
 
 	resty "github.com/go-resty/resty/v2"
@@ -24,15 +29,25 @@ type (
 	Client struct {
 		options               []ClientOption
 		retryer               *resty.Client
+		retryerConfig         *RetryerConfig
+		retryConditions       []retryCondition
+		clock                 clock.Clock
 		beforeRequest         []func(*request) error
 		afterRequest          []func(*Response, *request) (*Response, error)
 		logger                log.Logger
+		compression           *CompressionConfig
+		debug                 *DebugConfig
+		redactor              Redactor
 		isWithAuthTokenCalled bool
 	}
 
 	// ClientOption represents a functional option used to initialize a Client.
 	ClientOption func(*Client)
 
+	// retryCondition determines whether a request needs to be retried, based on its response or the error
+	// which prevented it from being executed.
+	retryCondition func(response *Response, err error) bool
+
 	// RetryerConfig defines the set of configuration options for retrying requests.
 	RetryerConfig struct {
 		maxRetries  uint
@@ -55,6 +70,12 @@ func NewClient(logger log.Logger, options ...ClientOption) *Client {
 		retryer: resty.New(),
 		options: options,
 		logger:  logger,
+		clock:   clock.System(),
+		debug:   NewDebugConfig(false, nil),
+	}
+
+	if logger != nil {
+		client.retryer.SetLogger(restyLogger{logger: logger})
 	}
 
 	for _, option := range options {
@@ -63,6 +84,7 @@ func NewClient(logger log.Logger, options ...ClientOption) *Client {
 
 	client.retryer.SetHeader(acceptHeader, applicationJSON).SetHeader(contentTypeHeader, applicationJSON)
 	client.withTraceLogging()
+	client.withDebugLogging()
 
 	return client
 }
@@ -92,9 +114,10 @@ func DefaultRetryerConfig() *RetryerConfig {
 	}
 }
 
-// Apply applies options to the client.
+// Apply applies options to the client. The debug config is kept unless the options replace it,
+// so that a debug mode enabled at runtime stays enabled.
 func (c *Client) Apply(options ...ClientOption) {
-	options = append(c.options, options...)
+	options = append(append([]ClientOption{WithDebug(c.debug)}, c.options...), options...)
 	*c = *NewClient(c.logger, options...)
 }
 
@@ -124,40 +147,79 @@ func (c *Client) Delete(url string, body interface{}, options ...RequestOption)
 }
 
 // ConfigureRetryer configures the Client's retryer.
+// Requests resulting in a retryable status or failing on the transport level, e.g. due to a connection reset,
+// are repeated with a capped exponential backoff, which is measured by the Client's clock.
+// Requests whose context is done are not repeated.
 func ConfigureRetryer(config *RetryerConfig) ClientOption {
 	return func(c *Client) {
 		if config == nil {
 			return
 		}
 
-		retryStatuses := []int{
-			http.StatusRequestTimeout,
-			http.StatusInternalServerError,
-			http.StatusBadGateway,
-			http.StatusServiceUnavailable,
-			http.StatusGatewayTimeout,
-		}
-
+		c.retryerConfig = config
+		c.retryConditions = []retryCondition{isRetryable}
 		c.retryer.
 			EnableTrace().
-			SetRetryWaitTime(config.waitTime).
-			SetRetryCount(int(config.maxRetries)).
-			SetRetryMaxWaitTime(config.maxWaitTime).
-			SetTimeout(config.timeout).
-			AddRetryCondition(
-				func(r *resty.Response, err error) bool {
-					for _, status := range retryStatuses {
-						if r.StatusCode() == status {
-							return true
-						}
-					}
-
-					return false
-				},
-			)
+			SetTimeout(config.timeout)
+	}
+}
+
+// WithClock configures the clock used to measure retry backoff.
+func WithClock(clk clock.Clock) ClientOption {
+	return func(c *Client) {
+		c.clock = clock.OrSystem(clk)
+	}
+}
+
+// shouldRetry determines whether the request needs to be retried, based on the response or error.
+func (c *Client) shouldRetry(response *Response, err error, request *request) bool {
+	for _, condition := range c.retryConditions {
+		if condition(response, err) {
+			c.logRetry(response, err, request)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// isRetryable determines whether the request failed temporarily, i.e. on the transport level or with
+// a retryable status.
+func isRetryable(response *Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	switch response.StatusCode() {
+	case http.StatusRequestTimeout,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
 	}
 }
 
+// backoff returns the capped exponential backoff with jitter for the attempt.
+func (c *RetryerConfig) backoff(attempt int) time.Duration {
+	capped := math.Min(float64(c.maxWaitTime), float64(c.waitTime)*math.Exp2(float64(attempt)))
+	half := int64(capped / 2)
+
+	wait := time.Duration(half)
+	if half > 0 {
+		wait += time.Duration(rand.Int63n(half)) //nolint: gosec
+	}
+
+	if wait < c.waitTime {
+		wait = c.waitTime
+	}
+
+	return wait
+}
+
 // JSONClient ensures that requests will be expected to send and receive JSON content, based on their headers.
 // JSONClient sets the accept and contentType headers.
 func JSONClient() ClientOption {
@@ -195,19 +257,50 @@ func (c *Client) withTraceLogging() {
 	after := func(response *Response, request *request) (*Response, error) {
 		traceInfo := request.baseRequest.TraceInfo()
 
-		c.logger.WithFields(map[string]interface{}{
+		fields := map[string]interface{}{
 			"response_time": traceInfo.ResponseTime,
 			"total_time":    traceInfo.TotalTime,
-			"url":           request.url,
+			"url":           c.redactorOrDefault().RedactURL(request.url),
 			"method":        request.method,
 			"status_code":   response.StatusCode(),
-		}).Debug("")
+		}
+
+		if request.sizes != nil {
+			fields["request_size"] = request.sizes.request
+			fields["request_uncompressed_size"] = request.sizes.requestUncompressed
+			fields["response_size"] = request.sizes.response
+			fields["response_uncompressed_size"] = request.sizes.responseUncompressed
+		}
+
+		c.logger.WithFields(fields).Debug("")
 
 		return response, nil
 	}
 	c.afterRequest = append(c.afterRequest, after)
 }
 
+// logRetry warns about a request which failed with a retryable status.
+func (c *Client) logRetry(response *Response, err error, request *request) {
+	if c.logger == nil {
+		return
+	}
+
+	fields := map[string]interface{}{
+		"url":    c.redactorOrDefault().RedactURL(request.url),
+		"method": request.method,
+	}
+
+	if err != nil {
+		fields["error"] = err.Error()
+		c.logger.WithFields(fields).Warn("triPica request failed, retrying")
+
+		return
+	}
+
+	fields["status_code"] = response.StatusCode()
+	c.logger.WithFields(fields).Warn("triPica request failed with a retryable status")
+}
+
 func (c *Client) withAuthToken(holder tokenHolder) {
 	before := func(request *request) error {
 		if request.skipAuthToken {
@@ -240,6 +333,7 @@ func (c *Client) withAuthToken(holder tokenHolder) {
 		}
 
 		request.shouldRepeat = false
+		response.close()
 
 		return request.execute()
 	}
@@ -283,6 +377,7 @@ func (c *Client) withBasicAuthToken(token string) {
 		}
 
 		request.shouldRepeat = false
+		response.close()
 
 		return request.execute()
 	}
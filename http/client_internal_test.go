@@ -1,8 +1,12 @@
 package http
 
 import (
+	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"syscall"
 	"testing"
 	"time"
 	"tripica-client/log"
@@ -69,24 +73,68 @@ func TestClient(t *testing.T) {
 func TestNewClient(t *testing.T) {
 	assert := assert.New(t)
 	client := NewClient(log.NewTestLogger(), ConfigureRetryer(NewRetryerConfig(2, 3, 4, 5)))
-	retryer := client.retryer
+	config := client.retryerConfig
 
-	assert.Equal(2, retryer.RetryCount)
-	assert.Equal(time.Duration(3000000), retryer.RetryWaitTime)
-	assert.Equal(time.Duration(4000000), retryer.RetryMaxWaitTime)
-	assert.Equal(time.Duration(5000000), retryer.GetClient().Timeout)
-	assert.Len(retryer.RetryConditions, 1)
+	assert.Equal(uint(2), config.maxRetries)
+	assert.Equal(time.Duration(3000000), config.waitTime)
+	assert.Equal(time.Duration(4000000), config.maxWaitTime)
+	assert.Equal(time.Duration(5000000), client.retryer.GetClient().Timeout)
+	assert.Len(client.retryConditions, 1)
 }
 
 // TestNewClient verifies that the configuration is properly applied to the client.
 func TestDefaultClient(t *testing.T) {
 	assert := assert.New(t)
 	client := DefaultClient(log.NewTestLogger())
-	retryer := client.retryer
+	config := client.retryerConfig
 
-	assert.Equal(4, retryer.RetryCount)
-	assert.Equal(time.Duration(1000000000), retryer.RetryWaitTime)
-	assert.Equal(time.Duration(2000000000), retryer.RetryMaxWaitTime)
-	assert.Equal(time.Duration(5000000000), retryer.GetClient().Timeout)
-	assert.Len(retryer.RetryConditions, 1)
+	assert.Equal(uint(4), config.maxRetries)
+	assert.Equal(time.Duration(1000000000), config.waitTime)
+	assert.Equal(time.Duration(2000000000), config.maxWaitTime)
+	assert.Equal(time.Duration(5000000000), client.retryer.GetClient().Timeout)
+	assert.Len(client.retryConditions, 1)
+}
+
+// TestIsRetryable verifies which responses and errors are retried.
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		err        error
+		retryable  bool
+	}{
+		{name: "service unavailable", statusCode: http.StatusServiceUnavailable, retryable: true},
+		{name: "gateway timeout", statusCode: http.StatusGatewayTimeout, retryable: true},
+		{name: "ok", statusCode: http.StatusOK},
+		{name: "bad request", statusCode: http.StatusBadRequest},
+		{name: "connection reset", err: syscall.ECONNRESET, retryable: true},
+		{name: "unexpected EOF", err: &url.Error{Op: "Get", URL: "/", Err: io.EOF}, retryable: true},
+		{name: "cancelled", err: &url.Error{Op: "Get", URL: "/", Err: context.Canceled}},
+		{name: "deadline exceeded", err: context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			var response *Response
+			if tt.err == nil {
+				response = NewResponse(nil, &http.Response{StatusCode: tt.statusCode})
+			}
+
+			assert.Equal(t, tt.retryable, isRetryable(response, tt.err))
+		})
+	}
+}
+
+// TestRetryerConfig_backoff verifies that the backoff grows exponentially, within the configured bounds.
+func TestRetryerConfig_backoff(t *testing.T) {
+	assert := assert.New(t)
+	config := NewRetryerConfig(4, 100, 1000, 5000)
+
+	for attempt := 0; attempt < 10; attempt++ {
+		wait := config.backoff(attempt)
+		assert.GreaterOrEqual(int64(wait), int64(100*time.Millisecond))
+		assert.Less(int64(wait), int64(1000*time.Millisecond))
+	}
 }
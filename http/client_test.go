@@ -1,12 +1,16 @@
 package http_test
 
 import (
+	"context"
 	"errors"
 	"io/ioutil"
 	stdhttp "net/http"
 	"net/http/httptest"
 	"net/url"
+	"sync/atomic"
 	"testing"
+	"time"
+	"tripica-client/clock"
 	"tripica-client/http"
 	httpmock "tripica-client/http/mock"
 	"tripica-client/log"
@@ -23,7 +27,15 @@ type (
 		require *require.Assertions
 	}
 	unauthorizedHandler struct{}
-	badRequestHandler   struct {
+	unavailableHandler  struct {
+		failures int32
+		calls    int32
+	}
+	resetHandler struct {
+		failures int32
+		calls    int32
+	}
+	badRequestHandler struct {
 		require *require.Assertions
 	}
 )
@@ -74,6 +86,33 @@ func (h *unauthorizedHandler) ServeHTTP(w stdhttp.ResponseWriter, r *stdhttp.Req
 	w.WriteHeader(stdhttp.StatusUnauthorized)
 }
 
+// ServeHTTP handles requests to unavailableHandler. It responds with StatusServiceUnavailable
+// until the configured number of failures is reached, and with StatusOK afterwards.
+func (h *unavailableHandler) ServeHTTP(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	if atomic.AddInt32(&h.calls, 1) <= h.failures {
+		w.WriteHeader(stdhttp.StatusServiceUnavailable)
+
+		return
+	}
+
+	w.WriteHeader(stdhttp.StatusOK)
+}
+
+// ServeHTTP handles requests to resetHandler. It closes the connection without responding
+// until the configured number of failures is reached, and responds with StatusOK afterwards.
+func (h *resetHandler) ServeHTTP(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	if atomic.AddInt32(&h.calls, 1) <= h.failures {
+		conn, _, err := w.(stdhttp.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+
+		return
+	}
+
+	w.WriteHeader(stdhttp.StatusOK)
+}
+
 func TestClient_Get(t *testing.T) {
 	runMethodTests(stdhttp.MethodGet, t)
 }
@@ -169,6 +208,94 @@ func TestWithAuthToken(t *testing.T) {
 	}
 }
 
+func TestConfigureRetryer(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("request is retried after the backoff measured by the clock", func(t *testing.T) {
+		h := unavailableHandler{failures: 2}
+		srv := httptest.NewServer(&h)
+		defer srv.Close()
+
+		clk := clock.NewFake(time.Now())
+		client := http.NewClient(
+			log.NewTestLogger(),
+			http.ConfigureRetryer(http.NewRetryerConfig(3, 60000, 60000, 1000)),
+			http.WithClock(clk),
+		)
+
+		done := make(chan *http.Response)
+		go func() {
+			res, err := client.Get(srv.URL)
+			assert.NoError(err)
+			done <- res
+		}()
+
+		for i := 0; i < 2; i++ {
+			clk.BlockUntil(1)
+			clk.Advance(time.Minute)
+		}
+
+		res := <-done
+		assert.Equal(stdhttp.StatusOK, res.StatusCode())
+		assert.Equal(int32(3), atomic.LoadInt32(&h.calls))
+	})
+
+	t.Run("request is not retried more than configured", func(t *testing.T) {
+		h := unavailableHandler{failures: 5}
+		srv := httptest.NewServer(&h)
+		defer srv.Close()
+
+		client := http.NewClient(log.NewTestLogger(), http.ConfigureRetryer(http.NewRetryerConfig(1, 1, 1, 1000)))
+
+		res, err := client.Get(srv.URL)
+		assert.NoError(err)
+		assert.Equal(stdhttp.StatusServiceUnavailable, res.StatusCode())
+		assert.Equal(int32(2), atomic.LoadInt32(&h.calls))
+	})
+
+	t.Run("transport errors are retried", func(t *testing.T) {
+		h := resetHandler{failures: 2}
+		srv := httptest.NewServer(&h)
+		defer srv.Close()
+
+		logger := log.NewCapturingLogger()
+		client := http.NewClient(logger, http.ConfigureRetryer(http.NewRetryerConfig(3, 1, 1, 1000)))
+
+		res, err := client.Get(srv.URL)
+		assert.NoError(err)
+		assert.Equal(stdhttp.StatusOK, res.StatusCode())
+		assert.Equal(int32(3), atomic.LoadInt32(&h.calls))
+		assert.Len(logger.EntriesWithLevel(log.LevelWarn), 2)
+	})
+
+	t.Run("transport errors are returned once the retries are exhausted", func(t *testing.T) {
+		h := resetHandler{failures: 5}
+		srv := httptest.NewServer(&h)
+		defer srv.Close()
+
+		client := http.NewClient(log.NewTestLogger(), http.ConfigureRetryer(http.NewRetryerConfig(1, 1, 1, 1000)))
+
+		_, err := client.Get(srv.URL)
+		assert.Error(err)
+		assert.Equal(int32(2), atomic.LoadInt32(&h.calls))
+	})
+
+	t.Run("cancelled requests are not retried", func(t *testing.T) {
+		h := resetHandler{failures: 5}
+		srv := httptest.NewServer(&h)
+		defer srv.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		client := http.NewClient(log.NewTestLogger(), http.ConfigureRetryer(http.NewRetryerConfig(3, 1, 1, 1000)))
+
+		_, err := client.Get(srv.URL, http.WithContext(ctx))
+		assert.True(errors.Is(err, context.Canceled), err)
+		assert.Equal(int32(0), atomic.LoadInt32(&h.calls))
+	})
+}
+
 func TestQueryParams(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
@@ -194,6 +321,55 @@ func TestQueryParams(t *testing.T) {
 	}
 }
 
+func TestWithContext(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	for _, method := range methods {
+		method := method
+		t.Run(method+" request with cancelled context fails", func(t *testing.T) {
+			h := handler{require: require}
+			srv := httptest.NewServer(&h)
+			defer srv.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			client := http.NewClient(log.NewTestLogger())
+
+			res, err := executeRequest(client, srv.URL, method, nil, http.WithContext(ctx))
+			assert.True(errors.Is(err, context.Canceled))
+			assert.Nil(res)
+		})
+	}
+}
+
+func TestStreamResponse(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	for _, method := range methods {
+		method := method
+		t.Run(method+" response body is left unread", func(t *testing.T) {
+			h := handler{require: require}
+			srv := httptest.NewServer(&h)
+			defer srv.Close()
+
+			client := http.NewClient(log.NewTestLogger())
+
+			res, err := executeRequest(client, srv.URL, method, nil, http.StreamResponse())
+			assert.NoError(err)
+			assert.Equal(stdhttp.StatusOK, res.StatusCode())
+			assert.Empty(res.Body())
+
+			body, err := ioutil.ReadAll(res.BodyReader())
+			assert.NoError(err)
+			assert.NoError(res.BodyReader().Close())
+			assert.Equal([]byte("message"), body)
+		})
+	}
+}
+
 func TestJSONClient(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
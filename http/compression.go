@@ -0,0 +1,214 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	acceptEncodingHeader      = "Accept-Encoding"
+	contentEncodingHeader     = "Content-Encoding"
+	encodingGzip              = "gzip"
+	encodingDeflate           = "deflate"
+	compressionMinRequestSize = 4096
+)
+
+type (
+	// CompressionConfig defines the set of configuration options for compressing requests and responses.
+	CompressionConfig struct {
+		minRequestSize int
+	}
+
+	// transferSizes holds the compressed (on the wire) and uncompressed sizes of a single exchange.
+	transferSizes struct {
+		request              int
+		requestUncompressed  int
+		response             int
+		responseUncompressed int
+	}
+
+	transferSizesKey struct{}
+
+	// decompressingTransport decodes gzip and deflate encoded response bodies,
+	// counting the number of bytes which were actually received over the wire.
+	decompressingTransport struct {
+		base http.RoundTripper
+	}
+
+	decompressingBody struct {
+		io.Reader
+		raw   io.ReadCloser
+		sizes *transferSizes
+	}
+
+	countingReader struct {
+		reader io.Reader
+		count  *int
+	}
+)
+
+// NewCompressionConfig returns an initialized CompressionConfig.
+// Request bodies which are at least minRequestSizeInBytes large are gzipped before being sent,
+// while a value of 0 disables request compression altogether.
+func NewCompressionConfig(minRequestSizeInBytes uint) *CompressionConfig {
+	return &CompressionConfig{
+		minRequestSize: int(minRequestSizeInBytes),
+	}
+}
+
+// DefaultCompressionConfig sets default compression config options.
+func DefaultCompressionConfig() *CompressionConfig {
+	return &CompressionConfig{
+		minRequestSize: compressionMinRequestSize,
+	}
+}
+
+// WithCompression configures the client to negotiate gzip and deflate encoded responses
+// and to transparently decompress them. Large request bodies are gzipped, depending on the config.
+// Compressed and uncompressed sizes are reported by the trace logging.
+func WithCompression(config *CompressionConfig) ClientOption {
+	return func(c *Client) {
+		if config == nil {
+			return
+		}
+
+		c.compression = config
+		c.retryer.
+			SetHeader(acceptEncodingHeader, encodingGzip+", "+encodingDeflate).
+			SetTransport(&decompressingTransport{base: c.retryer.GetClient().Transport})
+
+		c.beforeRequest = append(c.beforeRequest, c.compressRequest)
+	}
+}
+
+// compressRequest gzips the request body if it exceeds the configured size.
+// It also registers the request's transferSizes, so that the transport can report into it.
+func (c *Client) compressRequest(r *request) error {
+	if r.sizes != nil {
+		return nil
+	}
+
+	r.sizes = &transferSizes{}
+	r.baseRequest.SetContext(context.WithValue(r.baseRequest.Context(), transferSizesKey{}, r.sizes))
+
+	if r.body == nil {
+		return nil
+	}
+
+	body, err := encodeBody(r.body)
+	if err != nil {
+		return fmt.Errorf("couldn't encode request body: %w", err)
+	}
+
+	r.sizes.request = len(body)
+	r.sizes.requestUncompressed = len(body)
+
+	if c.compression.minRequestSize == 0 || len(body) < c.compression.minRequestSize {
+		return nil
+	}
+
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return fmt.Errorf("couldn't compress request body: %w", err)
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("couldn't compress request body: %w", err)
+	}
+
+	r.sizes.request = buf.Len()
+	r.baseRequest.SetHeader(contentEncodingHeader, encodingGzip)
+	r.baseRequest.SetBody(buf.Bytes())
+
+	return nil
+}
+
+// encodeBody returns the bytes which would be sent for the provided request body.
+func encodeBody(body interface{}) ([]byte, error) {
+	switch b := body.(type) {
+	case []byte:
+		return b, nil
+	case string:
+		return []byte(b), nil
+	default:
+		return json.Marshal(b)
+	}
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *decompressingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	sizes, ok := req.Context().Value(transferSizesKey{}).(*transferSizes)
+	if !ok {
+		sizes = &transferSizes{}
+	}
+
+	sizes.response, sizes.responseUncompressed = 0, 0
+	counted := &countingReader{reader: resp.Body, count: &sizes.response}
+
+	var decoded io.Reader
+
+	switch strings.ToLower(resp.Header.Get(contentEncodingHeader)) {
+	case encodingGzip:
+		decoded, err = gzip.NewReader(counted)
+	case encodingDeflate:
+		decoded, err = zlib.NewReader(counted)
+	default:
+		decoded = counted
+	}
+
+	if err == io.EOF {
+		decoded, err = bytes.NewReader(nil), nil
+	}
+
+	if err != nil {
+		resp.Body.Close()
+
+		return nil, fmt.Errorf("couldn't decompress response body: %w", err)
+	}
+
+	if decoded != counted {
+		resp.Header.Del(contentEncodingHeader)
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+	}
+
+	resp.Body = &decompressingBody{Reader: decoded, raw: resp.Body, sizes: sizes}
+
+	return resp, nil
+}
+
+// Read reads decoded data, keeping track of the uncompressed size.
+func (b *decompressingBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	b.sizes.responseUncompressed += n
+
+	return n, err
+}
+
+// Close closes the underlying response body.
+func (b *decompressingBody) Close() error {
+	return b.raw.Close()
+}
+
+// Read reads from the underlying reader, counting the number of bytes read.
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	*r.count += n
+
+	return n, err
+}
@@ -0,0 +1,97 @@
+package http_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"tripica-client/http"
+	"tripica-client/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gzipHandler responds with a gzipped body, storing the received request body and headers.
+type gzipHandler struct {
+	request []byte
+	header  stdhttp.Header
+	require *require.Assertions
+}
+
+// ServeHTTP handles requests sent to gzipHandler. Compressed request bodies are decompressed before being stored.
+func (h *gzipHandler) ServeHTTP(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	h.header = r.Header
+
+	body, err := ioutil.ReadAll(r.Body)
+	h.require.NoError(err)
+
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		h.require.NoError(err)
+
+		body, err = ioutil.ReadAll(zr)
+		h.require.NoError(err)
+	}
+
+	h.request = body
+
+	w.Header().Set("Content-Encoding", "gzip")
+	w.WriteHeader(stdhttp.StatusOK)
+
+	zw := gzip.NewWriter(w)
+	_, err = zw.Write([]byte(strings.Repeat("message", 100)))
+	h.require.NoError(err)
+	h.require.NoError(zw.Close())
+}
+
+func TestWithCompression(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	t.Run("response is transparently decompressed", func(t *testing.T) {
+		h := gzipHandler{require: require}
+		srv := httptest.NewServer(&h)
+		defer srv.Close()
+
+		client := http.NewClient(log.NewTestLogger(), http.WithCompression(http.DefaultCompressionConfig()))
+
+		res, err := client.Get(srv.URL)
+		assert.NoError(err)
+		assert.Equal(stdhttp.StatusOK, res.StatusCode())
+		assert.Equal([]byte(strings.Repeat("message", 100)), res.Body())
+		assert.Equal("gzip, deflate", h.header.Get("Accept-Encoding"))
+	})
+
+	t.Run("large request body is compressed", func(t *testing.T) {
+		h := gzipHandler{require: require}
+		srv := httptest.NewServer(&h)
+		defer srv.Close()
+
+		client := http.NewClient(log.NewTestLogger(), http.WithCompression(http.NewCompressionConfig(10)))
+		body := strings.Repeat("body", 10)
+
+		res, err := client.Post(srv.URL, body)
+		assert.NoError(err)
+		assert.Equal(stdhttp.StatusOK, res.StatusCode())
+		assert.Equal("gzip", h.header.Get("Content-Encoding"))
+		assert.Equal([]byte(body), h.request)
+	})
+
+	t.Run("small request body is sent uncompressed", func(t *testing.T) {
+		h := gzipHandler{require: require}
+		srv := httptest.NewServer(&h)
+		defer srv.Close()
+
+		client := http.NewClient(log.NewTestLogger(), http.WithCompression(http.DefaultCompressionConfig()))
+
+		res, err := client.Post(srv.URL, "body")
+		assert.NoError(err)
+		assert.Equal(stdhttp.StatusOK, res.StatusCode())
+		assert.Empty(h.header.Get("Content-Encoding"))
+		assert.Equal([]byte("body"), h.request)
+	})
+}
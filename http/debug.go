@@ -0,0 +1,171 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"tripica-client/redact"
+)
+
+const authorizationHeader = "Authorization"
+
+// credentialsRedactor masks credentials in dumps and rendered curl commands, regardless of the configured rules.
+var credentialsRedactor = redact.New(redact.Rule{Path: "password"}, redact.Rule{Path: "token"})
+
+// DebugConfig defines the set of configuration options for the debug mode.
+// The debug mode can be toggled at runtime, and is disabled by default.
+type DebugConfig struct {
+	enabled  int32
+	redactor Redactor
+}
+
+// NewDebugConfig returns an initialized DebugConfig. Request and response bodies are passed through
+// the provided redactor before being logged. If redactor is nil, the client's redactor is used.
+func NewDebugConfig(enabled bool, redactor Redactor) *DebugConfig {
+	config := &DebugConfig{redactor: redactor}
+	config.SetEnabled(enabled)
+
+	return config
+}
+
+// SetEnabled enables or disables the debug mode.
+func (d *DebugConfig) SetEnabled(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+
+	atomic.StoreInt32(&d.enabled, v)
+}
+
+// Enabled determines whether the debug mode is enabled.
+func (d *DebugConfig) Enabled() bool {
+	return atomic.LoadInt32(&d.enabled) == 1
+}
+
+// WithDebug configures the client to dump every request as a curl command, together with
+// the full request and response bodies, while the debug mode is enabled.
+func WithDebug(config *DebugConfig) ClientOption {
+	return func(c *Client) {
+		if config != nil {
+			c.debug = config
+		}
+	}
+}
+
+// SetDebug enables or disables the debug mode of the client at runtime.
+func (c *Client) SetDebug(enabled bool) {
+	c.debug.SetEnabled(enabled)
+}
+
+func (c *Client) withDebugLogging() {
+	after := func(response *Response, request *request) (*Response, error) {
+		if !c.debug.Enabled() {
+			return response, nil
+		}
+
+		fields := c.debugFields(request)
+		fields["status_code"] = response.StatusCode()
+		fields["response_body"] = string(c.redactDebugBody(response.Body()))
+
+		c.logger.WithFields(fields).Debug("triPica request dump")
+
+		return response, nil
+	}
+	c.afterRequest = append(c.afterRequest, after)
+}
+
+// debugFailure dumps a request which couldn't be executed.
+func (c *Client) debugFailure(request *request, err error) {
+	if c.debug == nil || !c.debug.Enabled() {
+		return
+	}
+
+	fields := c.debugFields(request)
+	fields["error"] = err.Error()
+
+	c.logger.WithFields(fields).Debug("triPica request dump")
+}
+
+// redactDebugBody passes the body through the debug redactor, and masks credentials on top of it,
+// since custom redactors may not cover them.
+func (c *Client) redactDebugBody(body []byte) []byte {
+	redactor := c.debug.redactor
+	if redactor == nil {
+		redactor = c.redactorOrDefault()
+	}
+
+	return credentialsRedactor.Redact(redactor.Redact(body))
+}
+
+func (c *Client) debugFields(request *request) map[string]interface{} {
+	var body []byte
+	if request.body != nil {
+		// The body was already successfully encoded while executing the request.
+		body, _ = encodeBody(request.body)
+		body = c.redactDebugBody(body)
+	}
+
+	fields := map[string]interface{}{
+		"url":          c.redactorOrDefault().RedactURL(request.url),
+		"method":       request.method,
+		"request_body": string(body),
+	}
+
+	if raw := request.baseRequest.RawRequest; raw != nil {
+		fields["curl"] = CurlCommand(raw, body)
+	}
+
+	return fields
+}
+
+// CurlCommand renders the request as a reproducible curl command. The Authorization header value
+// is masked, as well as passwords and tokens found in the provided body.
+func CurlCommand(req *http.Request, body []byte) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "curl -X %s %s", req.Method, shellQuote(req.URL.String()))
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range req.Header[name] {
+			switch {
+			case strings.EqualFold(name, authorizationHeader):
+				value = maskAuthorization(value)
+			case strings.EqualFold(name, contentEncodingHeader):
+				// The rendered body is never compressed.
+				continue
+			}
+
+			fmt.Fprintf(&b, " -H %s", shellQuote(name+": "+value))
+		}
+	}
+
+	if len(body) > 0 {
+		body = credentialsRedactor.Redact(body)
+		fmt.Fprintf(&b, " --data-raw %s", shellQuote(string(body)))
+	}
+
+	return b.String()
+}
+
+// maskAuthorization masks the credentials of an Authorization header value, keeping its scheme.
+func maskAuthorization(value string) string {
+	if i := strings.IndexByte(value, ' '); i > 0 {
+		return value[:i+1] + redact.Mask
+	}
+
+	return redact.Mask
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
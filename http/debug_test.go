@@ -0,0 +1,173 @@
+package http_test
+
+import (
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"tripica-client/http"
+	"tripica-client/log"
+	"tripica-client/redact"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurlCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("request is rendered with masked credentials", func(t *testing.T) {
+		req := httptest.NewRequest(stdhttp.MethodPost, "http://tripica/api/v1/login/jwt?x=1", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		req.Header.Set("Content-Type", "application/json")
+
+		cmd := http.CurlCommand(req, []byte(`{"email":"a@b.de","password":"secret"}`))
+
+		assert.Equal(
+			`curl -X POST 'http://tripica/api/v1/login/jwt?x=1'`+
+				` -H 'Authorization: Bearer ***' -H 'Content-Type: application/json'`+
+				` --data-raw '{"email":"a@b.de","password":"***"}'`,
+			cmd,
+		)
+	})
+
+	t.Run("single quotes are escaped", func(t *testing.T) {
+		req := httptest.NewRequest(stdhttp.MethodGet, "http://tripica/", nil)
+
+		cmd := http.CurlCommand(req, []byte(`it's`))
+
+		assert.Equal(`curl -X GET 'http://tripica/' --data-raw 'it'\''s'`, cmd)
+	})
+}
+
+func TestWithDebug(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	const dumpMessage = "triPica request dump"
+
+	dumps := func(logger *log.CapturingLogger) []log.Entry {
+		var entries []log.Entry
+
+		for _, e := range logger.EntriesWithLevel(log.LevelDebug) {
+			if e.Message == dumpMessage {
+				entries = append(entries, e)
+			}
+		}
+
+		return entries
+	}
+
+	t.Run("requests are only dumped while the debug mode is enabled", func(t *testing.T) {
+		h := handler{require: require}
+		srv := httptest.NewServer(&h)
+		defer srv.Close()
+
+		logger := log.NewCapturingLogger()
+		config := http.NewDebugConfig(false, nil)
+		client := http.NewClient(logger, http.WithDebug(config))
+
+		_, err := client.Post(srv.URL, map[string]string{"password": "secret"})
+		assert.NoError(err)
+		assert.Empty(dumps(logger))
+
+		client.SetDebug(true)
+		assert.True(config.Enabled())
+
+		res, err := client.Post(srv.URL, map[string]string{"password": "secret"})
+		assert.NoError(err)
+		assert.Equal(stdhttp.StatusOK, res.StatusCode())
+		assert.JSONEq(`{"password":"secret"}`, string(h.request))
+		assert.Len(dumps(logger), 1)
+
+		client.SetDebug(false)
+		assert.False(config.Enabled())
+
+		_, err = client.Post(srv.URL, map[string]string{"password": "secret"})
+		assert.NoError(err)
+		assert.Len(dumps(logger), 1)
+	})
+
+	t.Run("dumps contain the redacted curl command and bodies", func(t *testing.T) {
+		h := handler{require: require}
+		srv := httptest.NewServer(&h)
+		defer srv.Close()
+
+		logger := log.NewCapturingLogger()
+		client := http.NewClient(logger, http.WithDebug(http.NewDebugConfig(true, nil)))
+
+		_, err := client.Post(srv.URL, map[string]string{"password": "secret", "iban": "DE89370400440532013000"})
+		require.NoError(err)
+
+		entries := dumps(logger)
+		require.Len(entries, 1)
+
+		fields := entries[0].Fields
+		assert.Equal(stdhttp.MethodPost, fields["method"])
+		assert.Equal(stdhttp.StatusOK, fields["status_code"])
+		assert.Equal("message", fields["response_body"])
+		assert.JSONEq(`{"password":"***","iban":"***3000"}`, fields["request_body"].(string))
+
+		curl, ok := fields["curl"].(string)
+		require.True(ok)
+		assert.True(strings.HasPrefix(curl, "curl -X POST '"+srv.URL+"'"), curl)
+		assert.Contains(curl, `"password":"***"`)
+		assert.NotContains(curl, "secret")
+		assert.NotContains(curl, "DE89370400440532013000")
+	})
+
+	t.Run("dumps use the configured redactor", func(t *testing.T) {
+		h := handler{require: require}
+		srv := httptest.NewServer(&h)
+		defer srv.Close()
+
+		logger := log.NewCapturingLogger()
+		redactor := redact.New(redact.Rule{Path: "givenName"})
+		client := http.NewClient(logger, http.WithDebug(http.NewDebugConfig(true, redactor)))
+
+		_, err := client.Post(srv.URL, map[string]string{"givenName": "Max", "city": "Berlin"})
+		require.NoError(err)
+
+		entries := dumps(logger)
+		require.Len(entries, 1)
+		assert.JSONEq(`{"givenName":"***","city":"Berlin"}`, entries[0].Fields["request_body"].(string))
+	})
+
+	t.Run("credentials are masked on top of the configured redactor", func(t *testing.T) {
+		srv := httptest.NewServer(stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+			_, _ = w.Write([]byte(`{"token":"eyJhbGciOiJIUzI1NiJ9.secret"}`))
+		}))
+		defer srv.Close()
+
+		logger := log.NewCapturingLogger()
+		redactor := redact.New(redact.Rule{Path: "givenName"})
+		client := http.NewClient(logger, http.WithDebug(http.NewDebugConfig(true, redactor)))
+
+		_, err := client.Post(srv.URL, map[string]string{"email": "max@example.com", "password": "secret"})
+		require.NoError(err)
+
+		entries := dumps(logger)
+		require.Len(entries, 1)
+
+		fields := entries[0].Fields
+		assert.JSONEq(`{"email":"max@example.com","password":"***"}`, fields["request_body"].(string))
+		assert.JSONEq(`{"token":"***"}`, fields["response_body"].(string))
+		assert.NotContains(fields["curl"], "secret")
+	})
+
+	t.Run("the debug mode survives applying options", func(t *testing.T) {
+		h := handler{require: require}
+		srv := httptest.NewServer(&h)
+		defer srv.Close()
+
+		logger := log.NewCapturingLogger()
+		client := http.NewClient(logger)
+
+		client.SetDebug(true)
+		client.Apply(http.ConfigureRetryer(http.DefaultRetryerConfig()))
+
+		_, err := client.Get(srv.URL)
+		require.NoError(err)
+		assert.Len(dumps(logger), 1)
+	})
+}
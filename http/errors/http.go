@@ -3,6 +3,7 @@ package errors
 import (
 	"fmt"
 	"net/http"
+	"tripica-client/redact"
 )
 
 // HTTPError represents an error that can occur while making HTTP calls with triPica.
@@ -22,11 +23,12 @@ func NewHTTPError(err error, body []byte, statusCode int) *HTTPError {
 }
 
 // Error makes HTTPError implement the error interface.
+// PII within the body is masked using the default redactor, while the Body field remains intact.
 func (e *HTTPError) Error() string {
 	m := map[string]interface{}{}
 
 	if e.Body != "" {
-		m["body"] = e.Body
+		m["body"] = redact.Default().RedactString(e.Body)
 	}
 
 	if e.StatusCode != 0 {
@@ -0,0 +1,35 @@
+package errors_test
+
+import (
+	goerrors "errors"
+	"testing"
+	"tripica-client/http/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewParseError(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("PII of truncated bodies is masked", func(t *testing.T) {
+		body := []byte(`{"iban":"DE89370400440532013000","familyName":"Muster","token":"eyJhbGciOiJIUzI1NiJ9.ey`)
+
+		err := errors.NewParseError(goerrors.New("unexpected end of JSON input"), body)
+
+		assert.Contains(err.Error(), `"iban":"***3000"`)
+		assert.NotContains(err.Error(), "DE89370400440532013000")
+		assert.NotContains(err.Error(), "Muster")
+		assert.NotContains(err.Error(), "eyJhbGciOiJIUzI1NiJ9")
+		assert.Contains(err.Error(), "unexpected end of JSON input")
+	})
+
+	t.Run("the body is kept intact", func(t *testing.T) {
+		body := []byte(`{"iban":"DE89370400440532013000"`)
+
+		err := errors.NewParseError(goerrors.New("unexpected end of JSON input"), body)
+
+		var httpErr *errors.HTTPError
+		assert.True(goerrors.As(err, &httpErr))
+		assert.Equal(string(body), httpErr.Body)
+	})
+}
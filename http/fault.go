@@ -0,0 +1,169 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Possible fault types.
+const (
+	// FaultLatency delays the request by the fault's Latency, before sending it.
+	FaultLatency FaultType = iota
+	// FaultUnauthorized responds with 401 Unauthorized, without sending the request.
+	FaultUnauthorized
+	// FaultServerError responds with the fault's StatusCode (503 by default), without sending the request.
+	FaultServerError
+	// FaultConnectionReset fails the request with a connection reset error, without sending it.
+	FaultConnectionReset
+	// FaultMalformedJSON sends the request, but truncates the response body so it can't be parsed.
+	FaultMalformedJSON
+)
+
+type (
+	// FaultType represents the kind of failure a Fault injects.
+	FaultType int
+
+	// Fault describes a failure injected into a fraction of the matching requests.
+	// Method is matched case-insensitively, while Path is a path.Match pattern matched against the URL path.
+	// Empty values match every request. Rate is the probability, between 0 and 1, that a matching request fails.
+	Fault struct {
+		Type       FaultType
+		Method     string
+		Path       string
+		Rate       float64
+		Latency    time.Duration
+		StatusCode int
+	}
+
+	// FaultConfig defines the set of faults injected by the client.
+	FaultConfig struct {
+		faults []Fault
+		random *rand.Rand
+		mux    sync.Mutex
+	}
+
+	// faultTransport injects faults into the requests it sends.
+	faultTransport struct {
+		base   http.RoundTripper
+		config *FaultConfig
+	}
+)
+
+// NewFaultConfig returns an initialized FaultConfig. The seed makes the choice of failing requests reproducible.
+func NewFaultConfig(seed int64, faults ...Fault) *FaultConfig {
+	return &FaultConfig{
+		faults: faults,
+		random: rand.New(rand.NewSource(seed)), //nolint: gosec
+	}
+}
+
+// WithFaultInjection configures the client to inject faults into requests, allowing to verify
+// how retries, token re-authorization and parse errors are handled. It is meant for testing environments only.
+func WithFaultInjection(config *FaultConfig) ClientOption {
+	return func(c *Client) {
+		if config == nil || len(config.faults) == 0 {
+			return
+		}
+
+		c.retryer.SetTransport(&faultTransport{base: c.retryer.GetClient().Transport, config: config})
+	}
+}
+
+// matches determines whether the fault applies to the request.
+func (f *Fault) matches(req *http.Request) bool {
+	if f.Method != "" && !strings.EqualFold(f.Method, req.Method) {
+		return false
+	}
+
+	if f.Path == "" {
+		return true
+	}
+
+	matched, err := path.Match(f.Path, req.URL.Path)
+
+	return err == nil && matched
+}
+
+// roll determines whether the fault is injected, according to its rate.
+func (c *FaultConfig) roll(f *Fault) bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	return c.random.Float64() < f.Rate
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *faultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	malformed := false
+
+	for i := range t.config.faults {
+		f := &t.config.faults[i]
+		if !f.matches(req) || !t.config.roll(f) {
+			continue
+		}
+
+		switch f.Type {
+		case FaultLatency:
+			select {
+			case <-time.After(f.Latency):
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			}
+		case FaultUnauthorized:
+			return faultResponse(req, http.StatusUnauthorized), nil
+		case FaultServerError:
+			statusCode := f.StatusCode
+			if statusCode == 0 {
+				statusCode = http.StatusServiceUnavailable
+			}
+
+			return faultResponse(req, statusCode), nil
+		case FaultConnectionReset:
+			return nil, fmt.Errorf("injected fault: %w", syscall.ECONNRESET)
+		case FaultMalformedJSON:
+			malformed = true
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || !malformed {
+		return resp, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	body = append(body[:len(body)/2:len(body)/2], `{"`...)
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Del("Content-Length")
+
+	return resp, nil
+}
+
+// faultResponse returns an empty response with the provided status code.
+func faultResponse(req *http.Request, statusCode int) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		StatusCode:    statusCode,
+		Proto:         req.Proto,
+		ProtoMajor:    req.ProtoMajor,
+		ProtoMinor:    req.ProtoMinor,
+		Header:        http.Header{},
+		Body:          ioutil.NopCloser(bytes.NewReader(nil)),
+		ContentLength: 0,
+		Request:       req,
+	}
+}
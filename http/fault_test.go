@@ -0,0 +1,122 @@
+package http_test
+
+import (
+	"encoding/json"
+	"errors"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+	"tripica-client/http"
+	httpmock "tripica-client/http/mock"
+	"tripica-client/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nolint: funlen
+func TestWithFaultInjection(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	t.Run("server errors are injected into matching requests and retried", func(t *testing.T) {
+		h := handler{require: require}
+		srv := httptest.NewServer(&h)
+		defer srv.Close()
+
+		faults := http.NewFaultConfig(1, http.Fault{
+			Type:       http.FaultServerError,
+			Method:     stdhttp.MethodGet,
+			Path:       "/billing/*",
+			Rate:       1,
+			StatusCode: stdhttp.StatusBadGateway,
+		})
+		logger := log.NewCapturingLogger()
+		client := http.NewClient(
+			logger,
+			http.ConfigureRetryer(http.NewRetryerConfig(1, 1, 1, 1000)),
+			http.WithFaultInjection(faults),
+		)
+
+		res, err := client.Get(srv.URL + "/billing/1")
+		assert.NoError(err)
+		assert.Equal(stdhttp.StatusBadGateway, res.StatusCode())
+		logger.AssertLogged(t, log.LevelWarn, "triPica request failed with a retryable status", map[string]interface{}{
+			"status_code": stdhttp.StatusBadGateway,
+		})
+
+		res, err = client.Get(srv.URL + "/product/1")
+		assert.NoError(err)
+		assert.Equal(stdhttp.StatusOK, res.StatusCode())
+	})
+
+	t.Run("unauthorized responses trigger token re-authorization", func(t *testing.T) {
+		h := handler{require: require}
+		srv := httptest.NewServer(&h)
+		defer srv.Close()
+
+		tokenHolder := &httpmock.TokenHolder{}
+		tokenHolder.On("RefreshToken").Return(nil).Twice()
+		tokenHolder.On("InvalidateToken").Return().Twice()
+		tokenHolder.On("RawToken").Return("token").Twice()
+
+		faults := http.NewFaultConfig(1, http.Fault{Type: http.FaultUnauthorized, Rate: 1})
+		client := http.NewClient(log.NewTestLogger(), http.WithFaultInjection(faults), http.WithAuthToken(tokenHolder))
+
+		res, err := client.Get(srv.URL)
+		assert.NoError(err)
+		assert.Equal(stdhttp.StatusUnauthorized, res.StatusCode())
+		tokenHolder.AssertExpectations(t)
+	})
+
+	t.Run("connection resets fail the request", func(t *testing.T) {
+		h := handler{require: require}
+		srv := httptest.NewServer(&h)
+		defer srv.Close()
+
+		faults := http.NewFaultConfig(1, http.Fault{Type: http.FaultConnectionReset, Rate: 1})
+		client := http.NewClient(log.NewTestLogger(), http.WithFaultInjection(faults))
+
+		res, err := client.Get(srv.URL)
+		assert.True(errors.Is(err, syscall.ECONNRESET))
+		assert.Nil(res)
+	})
+
+	t.Run("malformed JSON can't be parsed", func(t *testing.T) {
+		h := handler{require: require}
+		srv := httptest.NewServer(&h)
+		defer srv.Close()
+
+		faults := http.NewFaultConfig(1, http.Fault{
+			Type: http.FaultMalformedJSON,
+			Rate: 1,
+		}, http.Fault{
+			Type:    http.FaultLatency,
+			Rate:    1,
+			Latency: 10 * time.Millisecond,
+		})
+		client := http.NewClient(log.NewTestLogger(), http.WithFaultInjection(faults))
+
+		res, err := client.Get(srv.URL)
+		assert.NoError(err)
+		assert.Equal(stdhttp.StatusOK, res.StatusCode())
+
+		var v interface{}
+		assert.Error(json.Unmarshal(res.Body(), &v))
+	})
+
+	t.Run("requests are not affected with a zero rate", func(t *testing.T) {
+		h := handler{require: require}
+		srv := httptest.NewServer(&h)
+		defer srv.Close()
+
+		faults := http.NewFaultConfig(1, http.Fault{Type: http.FaultConnectionReset})
+		client := http.NewClient(log.NewTestLogger(), http.WithFaultInjection(faults))
+
+		res, err := client.Get(srv.URL)
+		assert.NoError(err)
+		assert.Equal([]byte("message"), res.Body())
+	})
+}
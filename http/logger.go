@@ -0,0 +1,26 @@
+package http
+
+import (
+	"fmt"
+	"tripica-client/log"
+)
+
+// restyLogger adapts a log.Logger, so that messages of the underlying resty client are logged at proper levels.
+type restyLogger struct {
+	logger log.Logger
+}
+
+// Errorf logs at the error level.
+func (l restyLogger) Errorf(format string, v ...interface{}) {
+	l.logger.Error(fmt.Sprintf(format, v...))
+}
+
+// Warnf logs at the warn level.
+func (l restyLogger) Warnf(format string, v ...interface{}) {
+	l.logger.Warn(fmt.Sprintf(format, v...))
+}
+
+// Debugf logs at the debug level.
+func (l restyLogger) Debugf(format string, v ...interface{}) {
+	l.logger.Debug(fmt.Sprintf(format, v...))
+}
@@ -0,0 +1,26 @@
+package http
+
+import "tripica-client/redact"
+
+// Redactor removes sensitive information from bodies and URLs before they are logged.
+// It is implemented by redact.Redactor.
+type Redactor interface {
+	Redact(body []byte) []byte
+	RedactURL(url string) string
+}
+
+// WithRedactor configures the redactor applied to trace logs and debug dumps.
+// If the option isn't used, the client falls back to redact.Default().
+func WithRedactor(redactor Redactor) ClientOption {
+	return func(c *Client) {
+		c.redactor = redactor
+	}
+}
+
+func (c *Client) redactorOrDefault() Redactor {
+	if c.redactor != nil {
+		return c.redactor
+	}
+
+	return redact.Default()
+}
@@ -1,8 +1,10 @@
 package http
 
 import (
-	resty "github.com/go-resty/resty/v2"
+	"context"
 	"net/http"
+
+	resty "github.com/go-resty/resty/v2"
 )
 
 type request struct {
@@ -13,6 +15,8 @@ type request struct {
 	body          interface{}
 	shouldRepeat  bool
 	skipAuthToken bool
+	stream        bool
+	sizes         *transferSizes
 }
 
 // RequestOption represents a functional option used to initialize a Reqeust.
@@ -61,6 +65,26 @@ func QueryParams(params map[string]string) RequestOption {
 	}
 }
 
+// WithContext sets the context of the request, allowing it to be cancelled.
+func WithContext(ctx context.Context) RequestOption {
+	return func(r *request) *request {
+		r.baseRequest.SetContext(ctx)
+
+		return r
+	}
+}
+
+// StreamResponse leaves the response body unread, so that it can be consumed through Response.BodyReader.
+// The caller is responsible for closing the body.
+func StreamResponse() RequestOption {
+	return func(r *request) *request {
+		r.stream = true
+		r.baseRequest.SetDoNotParseResponse(true)
+
+		return r
+	}
+}
+
 // PostForm sets the Content-Type header to form.
 func PostForm() RequestOption {
 	return func(r *request) *request {
@@ -105,8 +129,10 @@ func (r *request) execute() (*Response, error) {
 		}
 	}
 
-	resp, err := r.baseExecute()
+	resp, err := r.executeWithRetries()
 	if err != nil {
+		r.client.debugFailure(r, err)
+
 		return nil, err
 	}
 
@@ -119,11 +145,42 @@ func (r *request) execute() (*Response, error) {
 	return resp, nil
 }
 
+// executeWithRetries executes the request, repeating it according to the client's retryer config.
+func (r *request) executeWithRetries() (*Response, error) {
+	config := r.client.retryerConfig
+	ctx := r.baseRequest.Context()
+
+	for attempt := 0; ; attempt++ {
+		resp, err := r.baseExecute()
+		if err != nil && ctx.Err() != nil {
+			return nil, err
+		}
+
+		if config == nil || attempt >= int(config.maxRetries) || !r.client.shouldRetry(resp, err, r) {
+			return resp, err
+		}
+
+		if resp != nil {
+			resp.close()
+		}
+
+		select {
+		case <-r.client.clock.After(config.backoff(attempt)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
 func (r *request) baseExecute() (*Response, error) {
 	baseResponse, err := r.baseRequest.Execute(r.method, r.url)
 	if err != nil {
 		return nil, err
 	}
 
+	if r.stream {
+		return NewStreamResponse(baseResponse.RawResponse), nil
+	}
+
 	return NewResponse(baseResponse.Body(), baseResponse.RawResponse), nil
 }
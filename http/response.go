@@ -1,6 +1,7 @@
 package http
 
 import (
+	"io"
 	"net/http"
 )
 
@@ -18,7 +19,20 @@ func NewResponse(body []byte, rawResponse *http.Response) *Response {
 	}
 }
 
+// NewStreamResponse returns a new Response whose body is read through BodyReader.
+func NewStreamResponse(rawResponse *http.Response) *Response {
+	return &Response{
+		rawResponse: rawResponse,
+	}
+}
+
+// BodyReader returns the unread response body of a streamed response. It needs to be closed by the caller.
+func (r *Response) BodyReader() io.ReadCloser {
+	return r.rawResponse.Body
+}
+
 // Body returns the response body as a []byte array.
+// The body is empty for responses of requests made with the StreamResponse option.
 func (r *Response) Body() []byte {
 	return r.body
 }
@@ -37,3 +51,10 @@ func (r *Response) Cookies() []*http.Cookie {
 func (r *Response) StatusCode() int {
 	return r.rawResponse.StatusCode
 }
+
+// close closes the unread body of a streamed response, which is about to be discarded.
+func (r *Response) close() {
+	if r.body == nil && r.rawResponse != nil && r.rawResponse.Body != nil {
+		r.rawResponse.Body.Close()
+	}
+}
@@ -0,0 +1,36 @@
+package http
+
+import (
+	"crypto/tls"
+	"net/http"
+)
+
+// WithTLSConfig configures the TLS settings of the client's transport, e.g. custom root CAs
+// or client certificates. It can be combined with options wrapping the transport, in any order.
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(c *Client) {
+		if config == nil {
+			return
+		}
+
+		if t := baseTransport(c.retryer.GetClient().Transport); t != nil {
+			t.TLSClientConfig = config.Clone()
+		}
+	}
+}
+
+// baseTransport unwraps the transport wrappers installed by client options, returning the underlying transport.
+func baseTransport(rt http.RoundTripper) *http.Transport {
+	for {
+		switch t := rt.(type) {
+		case *http.Transport:
+			return t
+		case *decompressingTransport:
+			rt = t.base
+		case *faultTransport:
+			rt = t.base
+		default:
+			return nil
+		}
+	}
+}
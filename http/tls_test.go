@@ -0,0 +1,45 @@
+package http_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"tripica-client/http"
+	"tripica-client/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTLSConfig(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	h := handler{require: require}
+	srv := httptest.NewTLSServer(&h)
+	defer srv.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+
+	t.Run("requests to servers with unknown certificates fail by default", func(t *testing.T) {
+		client := http.NewClient(log.NewTestLogger())
+
+		_, err := client.Get(srv.URL)
+		assert.Error(err)
+	})
+
+	t.Run("root CAs are applied beneath wrapping transports", func(t *testing.T) {
+		client := http.NewClient(
+			log.NewTestLogger(),
+			http.WithCompression(http.DefaultCompressionConfig()),
+			http.WithTLSConfig(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}),
+		)
+
+		res, err := client.Get(srv.URL)
+		assert.NoError(err)
+		assert.Equal(stdhttp.StatusOK, res.StatusCode())
+	})
+}
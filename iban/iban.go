@@ -0,0 +1,211 @@
+// Package iban validates, normalizes and formats IBANs and BICs of SEPA countries.
+package iban
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	countryGermany = "DE"
+	// maskedRune replaces the masked characters of an IBAN.
+	maskedRune = '*'
+	// visibleSuffix is the number of trailing characters left unmasked.
+	visibleSuffix = 4
+	groupSize     = 4
+)
+
+var (
+	// ErrInvalidCharacters is returned for values containing characters other than letters and digits.
+	ErrInvalidCharacters = errors.New("contains characters other than letters and digits")
+	// ErrUnsupportedCountry is returned for IBANs and BICs of countries outside of SEPA.
+	ErrUnsupportedCountry = errors.New("country isn't part of SEPA")
+	// ErrInvalidLength is returned for values which don't have the length required by their country, or format.
+	ErrInvalidLength = errors.New("invalid length")
+	// ErrInvalidChecksum is returned for IBANs whose check digits don't match.
+	ErrInvalidChecksum = errors.New("invalid checksum")
+	// ErrInvalidFormat is returned for values whose characters aren't in the expected places.
+	ErrInvalidFormat = errors.New("invalid format")
+
+	// lengths are the IBAN lengths of the SEPA countries.
+	lengths = map[string]int{
+		"AD": 24, "AT": 20, "BE": 16, "BG": 22, "CH": 21, "CY": 28, "CZ": 24, "DE": 22, "DK": 18, "EE": 20,
+		"ES": 24, "FI": 18, "FR": 27, "GB": 22, "GI": 23, "GR": 27, "HR": 21, "HU": 28, "IE": 22, "IS": 26,
+		"IT": 27, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "MC": 27, "MT": 31, "NL": 18, "NO": 15, "PL": 28,
+		"PT": 25, "RO": 24, "SE": 24, "SI": 19, "SK": 24, "SM": 27, "VA": 22,
+	}
+)
+
+// Normalize removes spaces, including non-breaking ones, and hyphens from the IBAN, and converts it to upper case.
+// It doesn't validate the IBAN.
+func Normalize(iban string) string {
+	return strings.ToUpper(strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' || r == '\t' || r == '\u00a0' {
+			return -1
+		}
+
+		return r
+	}, iban))
+}
+
+// Validate checks the length and check digits of the IBAN, which is normalized first.
+// Errors only contain the masked IBAN, so that they can be logged.
+func Validate(iban string) error {
+	iban = Normalize(iban)
+
+	if len(iban) < 4 {
+		return fmt.Errorf("IBAN %s: %w", Mask(iban), ErrInvalidLength)
+	}
+
+	if !isAlphanumeric(iban) {
+		return fmt.Errorf("IBAN %s: %w", Mask(iban), ErrInvalidCharacters)
+	}
+
+	if !isLetter(iban[0]) || !isLetter(iban[1]) || !isDigit(iban[2]) || !isDigit(iban[3]) {
+		return fmt.Errorf("IBAN %s: %w, expected a country code followed by check digits", Mask(iban), ErrInvalidFormat)
+	}
+
+	length, ok := lengths[iban[:2]]
+	if !ok {
+		return fmt.Errorf("IBAN %s: %w: %s", Mask(iban), ErrUnsupportedCountry, iban[:2])
+	}
+
+	if len(iban) != length {
+		return fmt.Errorf("IBAN %s: %w, %s IBANs have %d characters", Mask(iban), ErrInvalidLength, iban[:2], length)
+	}
+
+	if checksum(iban) != 1 {
+		return fmt.Errorf("IBAN %s: %w", Mask(iban), ErrInvalidChecksum)
+	}
+
+	return nil
+}
+
+// IsValid determines whether the IBAN is valid.
+func IsValid(iban string) bool {
+	return Validate(iban) == nil
+}
+
+// Country returns the country code of the IBAN.
+func Country(iban string) string {
+	iban = Normalize(iban)
+	if len(iban) < 2 {
+		return ""
+	}
+
+	return iban[:2]
+}
+
+// Format normalizes the IBAN, and groups it into blocks of four characters, e.g. "DE89 3704 0044 0532 0130 00".
+func Format(iban string) string {
+	return group(Normalize(iban))
+}
+
+// Mask normalizes the IBAN, and masks all but its country code and its last four characters,
+// grouped like Format, e.g. "DE** **** **** **** **30 00".
+func Mask(iban string) string {
+	iban = Normalize(iban)
+	if len(iban) <= 2+visibleSuffix {
+		return group(iban)
+	}
+
+	masked := []byte(iban)
+	for i := 2; i < len(masked)-visibleSuffix; i++ {
+		masked[i] = maskedRune
+	}
+
+	return group(string(masked))
+}
+
+// GermanBankCode returns the Bankleitzahl of a valid German IBAN.
+func GermanBankCode(iban string) (string, error) {
+	if err := Validate(iban); err != nil {
+		return "", err
+	}
+
+	iban = Normalize(iban)
+	if iban[:2] != countryGermany {
+		return "", fmt.Errorf("IBAN %s: %w, expected a German IBAN", Mask(iban), ErrInvalidFormat)
+	}
+
+	return iban[4:12], nil
+}
+
+// NormalizeBIC removes spaces from the BIC, and converts it to upper case. It doesn't validate the BIC.
+func NormalizeBIC(bic string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(bic), ""))
+}
+
+// ValidateBIC checks the format of the BIC, which is normalized first, and that its country is part of SEPA.
+func ValidateBIC(bic string) error {
+	bic = NormalizeBIC(bic)
+
+	if len(bic) != 8 && len(bic) != 11 {
+		return fmt.Errorf("BIC: %w, BICs have 8 or 11 characters", ErrInvalidLength)
+	}
+
+	if !isAlphanumeric(bic) {
+		return fmt.Errorf("BIC: %w", ErrInvalidCharacters)
+	}
+
+	for i := 0; i < 6; i++ {
+		if !isLetter(bic[i]) {
+			return fmt.Errorf("BIC: %w, expected a bank code followed by a country code", ErrInvalidFormat)
+		}
+	}
+
+	if _, ok := lengths[bic[4:6]]; !ok {
+		return fmt.Errorf("BIC: %w: %s", ErrUnsupportedCountry, bic[4:6])
+	}
+
+	return nil
+}
+
+// checksum returns the remainder of the IBAN's numeric representation divided by 97,
+// after moving its first four characters to its end.
+func checksum(iban string) int {
+	remainder := 0
+
+	for _, c := range []byte(iban[4:] + iban[:4]) {
+		if isDigit(c) {
+			remainder = (remainder*10 + int(c-'0')) % 97
+		} else {
+			remainder = (remainder*100 + int(c-'A') + 10) % 97
+		}
+	}
+
+	return remainder
+}
+
+func group(s string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if i > 0 && i%groupSize == 0 {
+			b.WriteByte(' ')
+		}
+
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+func isAlphanumeric(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isLetter(s[i]) && !isDigit(s[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isLetter(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,91 @@
+package iban_test
+
+import (
+	"errors"
+	"testing"
+	"tripica-client/iban"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, valid := range []string{
+		"DE89370400440532013000",
+		"de89 3704 0044 0532 0130 00",
+		"AT611904300234573201",
+		"NL91ABNA0417164300",
+		"NO9386011117947",
+		"MT84MALT011000012345MTLCAST001S",
+	} {
+		assert.NoError(iban.Validate(valid), valid)
+	}
+
+	for invalid, expected := range map[string]error{
+		"DE89370400440532013001":  iban.ErrInvalidChecksum,
+		"DE8937040044053201300":   iban.ErrInvalidLength,
+		"US64SVBKUS6S3300958879":  iban.ErrUnsupportedCountry,
+		"DE89_370400440532013000": iban.ErrInvalidCharacters,
+		"8989370400440532013000":  iban.ErrInvalidFormat,
+		"D":                       iban.ErrInvalidLength,
+	} {
+		assert.True(errors.Is(iban.Validate(invalid), expected), invalid)
+	}
+}
+
+func TestValidate_ErrorsAreMasked(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, invalid := range []string{
+		"DE89370400440532013001",
+		"DE8937040044053201300",
+		"US64SVBKUS6S3300958879",
+		"DE89_370400440532013000",
+		"8989370400440532013000",
+	} {
+		err := iban.Validate(invalid)
+		if assert.Error(err, invalid) {
+			assert.NotContains(err.Error(), invalid)
+			assert.NotContains(err.Error(), invalid[4:18])
+		}
+	}
+
+	_, err := iban.GermanBankCode("AT611904300234573201")
+	assert.Equal("IBAN AT** **** **** **** 3201: invalid format, expected a German IBAN", err.Error())
+
+	assert.NotContains(iban.ValidateBIC("CHASUS33").Error(), "CHAS")
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "DE89370400440532013000", iban.Normalize("de89 3704-0044\t0532\u00a00130 00"))
+}
+
+func TestFormatAndMask(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("DE89 3704 0044 0532 0130 00", iban.Format("de89-3704-0044-0532-0130-00"))
+	assert.Equal("DE** **** **** **** **30 00", iban.Mask("DE89370400440532013000"))
+	assert.Equal("DE", iban.Country("de89"))
+}
+
+func TestGermanBankCode(t *testing.T) {
+	assert := assert.New(t)
+
+	code, err := iban.GermanBankCode("DE89 3704 0044 0532 0130 00")
+	assert.NoError(err)
+	assert.Equal("37040044", code)
+
+	_, err = iban.GermanBankCode("AT611904300234573201")
+	assert.True(errors.Is(err, iban.ErrInvalidFormat))
+}
+
+func TestValidateBIC(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(iban.ValidateBIC("COBADEFFXXX"))
+	assert.NoError(iban.ValidateBIC("cobade ff"))
+	assert.True(errors.Is(iban.ValidateBIC("COBADEF"), iban.ErrInvalidLength))
+	assert.True(errors.Is(iban.ValidateBIC("COB1DEFF"), iban.ErrInvalidFormat))
+	assert.True(errors.Is(iban.ValidateBIC("CHASUS33"), iban.ErrUnsupportedCountry))
+}
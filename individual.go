@@ -1,34 +1,45 @@
 package tripica
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	gohttp "net/http"
+	"sort"
+	"strings"
 	"time"
 	"tripica-client/http"
 	"tripica-client/http/errors"
 	"tripica-client/log"
+	"tripica-client/phone"
 )
 
 const (
-	individualBasePath = "/api/private/v1/agent/individual"
+	individualBasePath = "/api/private/{version}/agent/individual"
 
 	individualPathGetByPartyOUID = "/%s"
+	individualPathContactMediums = "/%s/contactMedium"
+	individualPathContactMedium  = "/%s/contactMedium/%s"
 )
 
 // Individual manages individual related endpoints within triPica.
 type individualAPI struct {
 	httpClient *http.Client
 	address    string
+	endpoints  *endpointRegistry
+	decoder    *decoder
 
 	logger log.Logger
 }
 
 // GetIndividualByPartyOUID retrieves an individual by the customer's party OUID.
 func (i *individualAPI) GetIndividualByPartyOUID(partyOUID string) (*Individual, error) {
-	url := fmt.Sprintf(i.address+individualPathGetByPartyOUID, partyOUID)
+	return i.getIndividualByPartyOUID(context.Background(), partyOUID)
+}
+
+func (i *individualAPI) getIndividualByPartyOUID(ctx context.Context, partyOUID string) (*Individual, error) {
+	url := fmt.Sprintf(i.address+i.endpoints.path(EndpointIndividualByPartyOUID), partyOUID)
 
-	resp, err := i.httpClient.Get(url)
+	resp, err := i.httpClient.Get(url, http.WithContext(ctx))
 	if err != nil {
 		return nil, NewTriPicaError(errors.NewHTTPRequestError(err))
 	}
@@ -47,52 +58,112 @@ func (i *individualAPI) GetIndividualByPartyOUID(partyOUID string) (*Individual,
 	}
 
 	var individual *Individual
-	if err := json.Unmarshal(resp.Body(), &individual); err != nil {
+	if err := i.decoder.decode(i.endpoints.path(EndpointIndividualByPartyOUID), resp.Body(), &individual); err != nil {
 		return nil, NewTriPicaError(errors.NewParseError(err, resp.Body()))
 	}
 
 	return individual, nil
 }
 
+// Possible contact medium types.
+const (
+	ContactMediumTypeDeliveryAddress = ContactMediumType("DELIVERY_ADDRESS")
+	ContactMediumTypeBillingAddress  = ContactMediumType("BILLING_ADDRESS")
+)
+
+// Possible values of Medium.Type, which determine the variant of the medium.
 const (
-	contactMediumTypeDeliveryAddress = ContactMediumType("DELIVERY_ADDRESS")
-	contactMediumTypeBillingAddress  = ContactMediumType("BILLING_ADDRESS")
+	MediumKindPostalAddress = "POSTAL_ADDRESS"
+	MediumKindEmail         = "EMAIL"
+	MediumKindPhone         = "PHONE"
+	MediumKindMobile        = "MOBILE"
+	MediumKindFax           = "FAX"
 )
 
 // Individual represents a triPica individual.
 type Individual struct {
-	OUID           string          `json:"ouid"`
+	OUID           string          `json:"ouid" tripica:"required"`
+	Version        int             `json:"version"`
 	Title          string          `json:"title"`
 	Name           string          `json:"givenName"`
 	LastName       string          `json:"familyName"`
 	Gender         string          `json:"gender"`
 	ContactMediums []ContactMedium `json:"contactMediums"`
+	Extensions     Extensions      `json:"-"`
 }
 
 // DeliveryAddress returns the delivery address medium.
 func (i *Individual) DeliveryAddress(now time.Time) MediumTypeAddress {
-	return i.contactMedium(now, contactMediumTypeDeliveryAddress).Medium.MediumTypeAddress
+	return i.contactMedium(now, ContactMediumTypeDeliveryAddress).Medium.MediumTypeAddress
 }
 
 // BillingAddress returns the billing address medium.
 func (i *Individual) BillingAddress(now time.Time) MediumTypeAddress {
-	return i.contactMedium(now, contactMediumTypeBillingAddress).Medium.MediumTypeAddress
+	return i.contactMedium(now, ContactMediumTypeBillingAddress).Medium.MediumTypeAddress
 }
 
-// contactMedium searches through the contact mediums for the specified type.
-// It returns an empty contact medium in case none is found, since zero values
-// will be omitted when sending them to collectAI.
-func (i *Individual) contactMedium(now time.Time, typ ContactMediumType) ContactMedium {
+// PrimaryEmail returns the address of the preferred email medium valid at the provided time, falling back to
+// the most recently started one, or an empty string if there is none.
+func (i *Individual) PrimaryEmail(now time.Time) string {
+	emails := i.Emails(now)
+	if len(emails) == 0 {
+		return ""
+	}
+
+	return emails[0].Medium.EmailAddress
+}
+
+// Emails returns the email mediums valid at the provided time, preferred ones first, followed by the
+// most recently started ones.
+func (i *Individual) Emails(now time.Time) []ContactMedium {
+	return i.validMediums(now, (*Medium).IsEmail)
+}
+
+// PrimaryPhone returns the number of the preferred phone medium valid at the provided time, falling back to
+// the most recently started one, or an empty string if there is none.
+func (i *Individual) PrimaryPhone(now time.Time) string {
+	phones := i.Phones(now)
+	if len(phones) == 0 {
+		return ""
+	}
+
+	return phones[0].Medium.Number
+}
+
+// Phones returns the phone mediums, including mobile and fax numbers, valid at the provided time,
+// preferred ones first, followed by the most recently started ones.
+func (i *Individual) Phones(now time.Time) []ContactMedium {
+	return i.validMediums(now, (*Medium).IsPhone)
+}
+
+// validMediums returns the contact mediums of the variant valid at the provided time, ordered by preference.
+func (i *Individual) validMediums(now time.Time, variant func(*Medium) bool) []ContactMedium {
+	mediums := make([]ContactMedium, 0, len(i.ContactMediums))
+
 	for _, cm := range i.ContactMediums {
-		if cm.Type == typ &&
-			// We should only consider mediums which have startDateTime in the past,
-			// and EndDateTime which is either nil or in the future.
-			cm.StartDateTime.Before(now) && (cm.EndDateTime == nil || now.Before(cm.EndDateTime.Time)) {
-			return cm
+		if variant(&cm.Medium) && cm.IsValidAt(now) {
+			mediums = append(mediums, cm)
 		}
 	}
 
-	return ContactMedium{}
+	sort.SliceStable(mediums, func(a, b int) bool {
+		if mediums[a].Preferred != mediums[b].Preferred {
+			return mediums[a].Preferred
+		}
+
+		return mediums[a].StartDateTime.After(mediums[b].StartDateTime.Time)
+	})
+
+	return mediums
+}
+
+// contactMedium searches through the contact mediums for the specified type, resolving overlaps like
+// ContactMediumTimeline.At. It returns an empty contact medium in case none is found, since zero values
+// will be omitted when sending them to collectAI.
+func (i *Individual) contactMedium(now time.Time, typ ContactMediumType) ContactMedium {
+	cm, _ := i.Timeline(typ).At(now)
+
+	return cm
 }
 
 // ContactMediumType represents a possible contact media type.
@@ -100,26 +171,124 @@ type ContactMediumType string
 
 // ContactMedium represents triPica individual's contact mediums.
 type ContactMedium struct {
-	OUID          string            `json:"ouid"`
+	OUID          string            `json:"ouid,omitempty"`
 	Preferred     bool              `json:"prefered"`
-	Type          ContactMediumType `json:"type"`
-	StartDateTime Date              `json:"startDateTime"`
-	EndDateTime   *Date             `json:"endDateTime"`
-	Medium        `json:"medium"`
+	Type          ContactMediumType `json:"type" tripica:"required"`
+	StartDateTime Date              `json:"startDateTime" tripica:"required"`
+	EndDateTime   Date              `json:"endDateTime"`
+	Medium        `json:"medium" tripica:"required"`
+	Extensions    Extensions `json:"-"`
+}
+
+// IsValidAt determines whether the contact medium is valid at the provided time, i.e. whether its validity
+// contains the time: the StartDateTime is included, and the EndDateTime, unless unset, is excluded.
+func (c *ContactMedium) IsValidAt(now time.Time) bool {
+	return c.Validity().Contains(now)
 }
 
-// Medium represents a single Medium.
+// Medium represents a single Medium. triPica returns the attributes of all variants within the same object,
+// and Type determines which of them apply, see IsAddress, IsEmail and IsPhone.
 type Medium struct {
-	OUID string `json:"ouid"` //nolint: misspell
+	OUID string `json:"ouid,omitempty"` //nolint: misspell
 	MediumTypeAddress
+	MediumTypeEmail
+	MediumTypePhone
 	Type string `json:"type"`
 }
 
+// IsEmail determines whether the medium is an email address.
+func (m *Medium) IsEmail() bool {
+	return strings.EqualFold(m.Type, MediumKindEmail)
+}
+
+// IsPhone determines whether the medium is a phone, mobile or fax number.
+func (m *Medium) IsPhone() bool {
+	return strings.EqualFold(m.Type, MediumKindPhone) || strings.EqualFold(m.Type, MediumKindMobile) ||
+		strings.EqualFold(m.Type, MediumKindFax)
+}
+
+// IsAddress determines whether the medium is a postal address. Mediums without a type are considered
+// addresses, since triPica didn't set the type of address mediums in the past.
+func (m *Medium) IsAddress() bool {
+	return m.Type == "" || strings.EqualFold(m.Type, MediumKindPostalAddress)
+}
+
+// Address returns the address variant of the medium, and whether the medium is an address.
+func (m *Medium) Address() (MediumTypeAddress, bool) {
+	if !m.IsAddress() {
+		return MediumTypeAddress{}, false
+	}
+
+	return m.MediumTypeAddress, true
+}
+
+// Email returns the email variant of the medium, and whether the medium is an email address.
+func (m *Medium) Email() (MediumTypeEmail, bool) {
+	if !m.IsEmail() {
+		return MediumTypeEmail{}, false
+	}
+
+	return m.MediumTypeEmail, true
+}
+
+// Phone returns the phone variant of the medium, and whether the medium is a phone number.
+func (m *Medium) Phone() (MediumTypePhone, bool) {
+	if !m.IsPhone() {
+		return MediumTypePhone{}, false
+	}
+
+	return m.MediumTypePhone, true
+}
+
 // MediumTypeAddress represents one of the possible medium types for an individual.
 type MediumTypeAddress struct {
-	City     string `json:"city"`
-	Country  string `json:"country"`
-	Street1  string `json:"street1"`
-	Street2  string `json:"street2"`
-	Postcode string `json:"postCode"`
+	City     string `json:"city,omitempty"`
+	Country  string `json:"country,omitempty"`
+	Street1  string `json:"street1,omitempty"`
+	Street2  string `json:"street2,omitempty"`
+	Postcode string `json:"postCode,omitempty"`
+}
+
+// MediumTypeEmail represents the email variant of a medium.
+type MediumTypeEmail struct {
+	EmailAddress string `json:"emailAddress,omitempty"`
+}
+
+// MediumTypePhone represents the phone variant of a medium. triPica doesn't enforce a format, see E164.
+type MediumTypePhone struct {
+	Number string `json:"number,omitempty"`
+}
+
+// E164 returns the number in E.164 format, e.g. "+4930123456". National numbers are interpreted using the
+// default country calling code, e.g. "49", which may be looked up by phone.CountryCallingCode.
+func (p MediumTypePhone) E164(defaultCountryCode string) (string, error) {
+	return phone.Normalize(p.Number, defaultCountryCode)
+}
+
+// UnmarshalJSON decodes the Individual, retaining undeclared properties in its Extensions.
+func (i *Individual) UnmarshalJSON(data []byte) error {
+	type individual Individual
+
+	return unmarshalExtended(data, (*individual)(i), &i.Extensions)
+}
+
+// MarshalJSON encodes the Individual, including its Extensions.
+func (i Individual) MarshalJSON() ([]byte, error) {
+	type individual Individual
+
+	return marshalExtended(individual(i), i.Extensions)
+}
+
+// UnmarshalJSON decodes the ContactMedium, retaining undeclared properties in its Extensions.
+func (c *ContactMedium) UnmarshalJSON(data []byte) error {
+	type contactMedium ContactMedium
+
+	return unmarshalExtended(data, (*contactMedium)(c), &c.Extensions)
+}
+
+// MarshalJSON encodes the ContactMedium, including its Extensions.
+func (c ContactMedium) MarshalJSON() ([]byte, error) {
+	type contactMedium ContactMedium
+
+	return marshalExtended(contactMedium(c), c.Extensions)
 }
@@ -31,9 +31,14 @@ func NewToken(raw string) (*Token, error) {
 
 // IsExpired checks whether the token has expired.
 func (t *Token) IsExpired() bool {
+	return t.IsExpiredAt(time.Now())
+}
+
+// IsExpiredAt checks whether the token has expired at the provided time.
+func (t *Token) IsExpiredAt(now time.Time) bool {
 	required := true
 
-	return !t.claims.VerifyExpiresAt(time.Now().Unix(), required)
+	return !t.claims.VerifyExpiresAt(now.Unix(), required)
 }
 
 // parseClaims returns the JWT claims without checking the header or signature.
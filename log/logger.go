@@ -1,6 +1,22 @@
 package log
 
+// Logger is the logging interface used throughout the library.
+// Fields added with WithFields are attached to every entry logged by the returned Logger.
 type Logger interface {
 	WithFields(map[string]interface{}) Logger
 	Debug(v ...interface{})
+	Info(v ...interface{})
+	Warn(v ...interface{})
+	Error(v ...interface{})
 }
+
+// Level represents the severity of a log entry.
+type Level string
+
+// Possible log levels.
+const (
+	LevelDebug Level = "debug"
+	LevelInfo  Level = "info"
+	LevelWarn  Level = "warn"
+	LevelError Level = "error"
+)
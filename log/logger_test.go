@@ -0,0 +1,57 @@
+package log_test
+
+import (
+	"bytes"
+	stdlog "log"
+	"testing"
+	"tripica-client/log"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStdLogger_WithFields(t *testing.T) {
+	assert := assert.New(t)
+
+	logger := log.NewTestLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	logger.WithFields(map[string]interface{}{"url": "/customer"}).WithFields(map[string]interface{}{
+		"status_code": 200,
+	}).Info("done")
+
+	output := logger.GetOutput()
+	assert.Len(output, 1)
+	assert.Contains(output[0], "level=info")
+	assert.Contains(output[0], `msg=done`)
+	assert.Contains(output[0], "url=/customer")
+	assert.Contains(output[0], "status_code=200")
+}
+
+func TestNewStdLib(t *testing.T) {
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+	logger := log.NewStdLib(stdlog.New(&buf, "", 0), log.LevelInfo)
+
+	logger.Debug("hidden")
+	logger.WithFields(map[string]interface{}{"b": 2, "a": 1}).Warn("retrying")
+
+	assert.Equal("level=warn msg=\"retrying\" a=1 b=2\n", buf.String())
+}
+
+func TestCapturingLogger(t *testing.T) {
+	assert := assert.New(t)
+
+	logger := log.NewCapturingLogger()
+	logger.WithFields(map[string]interface{}{"status_code": 503, "url": "/product"}).Warn("retrying")
+	logger.Debug("trace")
+
+	logger.AssertLogged(t, log.LevelWarn, "retrying", map[string]interface{}{"status_code": 503})
+	logger.AssertNotLogged(t, log.LevelError)
+	assert.Len(logger.Entries(), 2)
+	assert.Len(logger.EntriesWithLevel(log.LevelDebug), 1)
+
+	logger.Reset()
+	assert.Empty(logger.Entries())
+}
@@ -1,6 +1,12 @@
 package log
 
-import "github.com/sirupsen/logrus"
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
 
 // StdLogger unifies two loggers implementations by implementing types logrus.FieldLogger and echo.Logger.
 type StdLogger struct {
@@ -24,11 +30,10 @@ func NewTestLogger() *StdLogger {
 	return logger
 }
 
+// WithFields returns a Logger which attaches the fields to every entry,
+// while sharing the output and level of the StdLogger.
 func (l *StdLogger) WithFields(fields map[string]interface{}) Logger {
-	log := logrus.New()
-	log.WithFields(fields)
-
-	return &StdLogger{log}
+	return NewLogrus(l.Logger.WithFields(fields))
 }
 
 // GetOutput returns all logged content, which could be easily used in assertions.
@@ -46,3 +51,143 @@ func (l *loggerOut) Write(data []byte) (int, error) {
 
 	return len(data), nil
 }
+
+type (
+	// CapturingLogger is a Logger which records every entry in memory, allowing structured assertions in tests.
+	CapturingLogger struct {
+		fields  map[string]interface{}
+		entries *capturedEntries
+	}
+
+	// Entry represents a single captured log entry.
+	Entry struct {
+		Level   Level
+		Message string
+		Fields  map[string]interface{}
+	}
+
+	// TestingT is the subset of testing.TB used by the CapturingLogger assertions.
+	TestingT interface {
+		Errorf(format string, args ...interface{})
+		Helper()
+	}
+
+	capturedEntries struct {
+		entries []Entry
+		mux     sync.Mutex
+	}
+)
+
+// NewCapturingLogger creates a new CapturingLogger.
+func NewCapturingLogger() *CapturingLogger {
+	return &CapturingLogger{entries: &capturedEntries{}}
+}
+
+// WithFields returns a Logger which attaches the fields to every entry.
+// Entries logged by the returned Logger are captured by the parent CapturingLogger as well.
+func (l *CapturingLogger) WithFields(fields map[string]interface{}) Logger {
+	return &CapturingLogger{
+		fields:  mergeFields(l.fields, fields),
+		entries: l.entries,
+	}
+}
+
+// Debug captures an entry at the debug level.
+func (l *CapturingLogger) Debug(v ...interface{}) {
+	l.capture(LevelDebug, v)
+}
+
+// Info captures an entry at the info level.
+func (l *CapturingLogger) Info(v ...interface{}) {
+	l.capture(LevelInfo, v)
+}
+
+// Warn captures an entry at the warn level.
+func (l *CapturingLogger) Warn(v ...interface{}) {
+	l.capture(LevelWarn, v)
+}
+
+// Error captures an entry at the error level.
+func (l *CapturingLogger) Error(v ...interface{}) {
+	l.capture(LevelError, v)
+}
+
+// Entries returns all captured entries, in the order they were logged.
+func (l *CapturingLogger) Entries() []Entry {
+	l.entries.mux.Lock()
+	defer l.entries.mux.Unlock()
+
+	return append([]Entry(nil), l.entries.entries...)
+}
+
+// EntriesWithLevel returns the captured entries of the provided level.
+func (l *CapturingLogger) EntriesWithLevel(level Level) []Entry {
+	var entries []Entry
+
+	for _, e := range l.Entries() {
+		if e.Level == level {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries
+}
+
+// Reset removes all captured entries.
+func (l *CapturingLogger) Reset() {
+	l.entries.mux.Lock()
+	l.entries.entries = nil
+	l.entries.mux.Unlock()
+}
+
+// AssertLogged asserts that an entry with the level and message was captured,
+// containing at least the provided fields.
+func (l *CapturingLogger) AssertLogged(t TestingT, level Level, message string, fields map[string]interface{}) bool {
+	t.Helper()
+
+	for _, e := range l.Entries() {
+		if e.Level == level && e.Message == message && e.hasFields(fields) {
+			return true
+		}
+	}
+
+	t.Errorf("no %s entry %q with fields %v was logged, got: %v", level, message, fields, l.Entries())
+
+	return false
+}
+
+// AssertNotLogged asserts that no entry of the level was captured.
+func (l *CapturingLogger) AssertNotLogged(t TestingT, level Level) bool {
+	t.Helper()
+
+	if entries := l.EntriesWithLevel(level); len(entries) > 0 {
+		t.Errorf("expected no %s entries to be logged, got: %v", level, entries)
+
+		return false
+	}
+
+	return true
+}
+
+func (l *CapturingLogger) capture(level Level, v []interface{}) {
+	l.entries.mux.Lock()
+	defer l.entries.mux.Unlock()
+
+	l.entries.entries = append(l.entries.entries, Entry{
+		Level:   level,
+		Message: fmt.Sprint(v...),
+		Fields:  mergeFields(l.fields, nil),
+	})
+}
+
+// hasFields determines whether the entry contains all of the provided fields.
+func (e Entry) hasFields(fields map[string]interface{}) bool {
+	for key, value := range fields {
+		actual, ok := e.Fields[key]
+		if !ok || !reflect.DeepEqual(actual, value) {
+			return false
+		}
+	}
+
+	return true
+}
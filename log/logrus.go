@@ -0,0 +1,18 @@
+package log
+
+import "github.com/sirupsen/logrus"
+
+// logrusLogger adapts a logrus logger or entry to the Logger interface.
+type logrusLogger struct {
+	logrus.FieldLogger
+}
+
+// NewLogrus returns a Logger writing to the provided logrus.Logger or logrus.Entry.
+func NewLogrus(logger logrus.FieldLogger) Logger {
+	return &logrusLogger{FieldLogger: logger}
+}
+
+// WithFields returns a Logger which attaches the fields to every entry.
+func (l *logrusLogger) WithFields(fields map[string]interface{}) Logger {
+	return &logrusLogger{FieldLogger: l.FieldLogger.WithFields(fields)}
+}
@@ -0,0 +1,68 @@
+//go:build go1.21
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+)
+
+// slogLogger adapts a log/slog Handler to the Logger interface.
+// It is only available when building with Go 1.21 or later.
+type slogLogger struct {
+	handler slog.Handler
+}
+
+// NewSlog returns a Logger writing to the provided log/slog Handler.
+func NewSlog(handler slog.Handler) Logger {
+	return &slogLogger{handler: handler}
+}
+
+// WithFields returns a Logger which attaches the fields to every entry.
+func (l *slogLogger) WithFields(fields map[string]interface{}) Logger {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, key := range keys {
+		attrs = append(attrs, slog.Any(key, fields[key]))
+	}
+
+	return &slogLogger{handler: l.handler.WithAttrs(attrs)}
+}
+
+// Debug logs at the debug level.
+func (l *slogLogger) Debug(v ...interface{}) {
+	l.log(slog.LevelDebug, v)
+}
+
+// Info logs at the info level.
+func (l *slogLogger) Info(v ...interface{}) {
+	l.log(slog.LevelInfo, v)
+}
+
+// Warn logs at the warn level.
+func (l *slogLogger) Warn(v ...interface{}) {
+	l.log(slog.LevelWarn, v)
+}
+
+// Error logs at the error level.
+func (l *slogLogger) Error(v ...interface{}) {
+	l.log(slog.LevelError, v)
+}
+
+func (l *slogLogger) log(level slog.Level, v []interface{}) {
+	ctx := context.Background()
+	if !l.handler.Enabled(ctx, level) {
+		return
+	}
+
+	logger := slog.New(l.handler)
+	logger.Log(ctx, level, fmt.Sprint(v...))
+}
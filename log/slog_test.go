@@ -0,0 +1,33 @@
+//go:build go1.21
+
+package log_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"tripica-client/log"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSlog(t *testing.T) {
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	})
+	logger := log.NewSlog(handler)
+
+	logger.Debug("hidden")
+	logger.WithFields(map[string]interface{}{"url": "/billing"}).Error("failed")
+
+	assert.Equal("level=ERROR msg=failed url=/billing\n", buf.String())
+}
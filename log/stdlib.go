@@ -0,0 +1,108 @@
+package log
+
+import (
+	"fmt"
+	stdlog "log"
+	"sort"
+	"strings"
+)
+
+// stdLibLogger adapts a standard library log.Logger to the Logger interface.
+// Entries are written as a single line containing the level, message and fields.
+type stdLibLogger struct {
+	logger *stdlog.Logger
+	level  Level
+	fields map[string]interface{}
+}
+
+// NewStdLib returns a Logger writing to the provided standard library logger.
+// Entries below the provided level are discarded.
+func NewStdLib(logger *stdlog.Logger, level Level) Logger {
+	return &stdLibLogger{
+		logger: logger,
+		level:  level,
+	}
+}
+
+// WithFields returns a Logger which attaches the fields to every entry.
+func (l *stdLibLogger) WithFields(fields map[string]interface{}) Logger {
+	return &stdLibLogger{
+		logger: l.logger,
+		level:  l.level,
+		fields: mergeFields(l.fields, fields),
+	}
+}
+
+// Debug logs at the debug level.
+func (l *stdLibLogger) Debug(v ...interface{}) {
+	l.log(LevelDebug, v)
+}
+
+// Info logs at the info level.
+func (l *stdLibLogger) Info(v ...interface{}) {
+	l.log(LevelInfo, v)
+}
+
+// Warn logs at the warn level.
+func (l *stdLibLogger) Warn(v ...interface{}) {
+	l.log(LevelWarn, v)
+}
+
+// Error logs at the error level.
+func (l *stdLibLogger) Error(v ...interface{}) {
+	l.log(LevelError, v)
+}
+
+func (l *stdLibLogger) log(level Level, v []interface{}) {
+	if level.severity() < l.level.severity() {
+		return
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "level=%s msg=%q", level, fmt.Sprint(v...))
+
+	keys := make([]string, 0, len(l.fields))
+	for key := range l.fields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fmt.Fprintf(&b, " %s=%v", key, l.fields[key])
+	}
+
+	l.logger.Print(b.String())
+}
+
+// severity returns the numeric severity of the level, so that levels can be compared.
+func (l Level) severity() int {
+	switch l {
+	case LevelDebug:
+		return 0
+	case LevelInfo:
+		return 1
+	case LevelWarn:
+		return 2
+	case LevelError:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// mergeFields returns a new map containing the fields of both maps, with the latter taking precedence.
+func mergeFields(fields, other map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(fields)+len(other))
+
+	for key, value := range fields {
+		merged[key] = value
+	}
+
+	for key, value := range other {
+		merged[key] = value
+	}
+
+	return merged
+}
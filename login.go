@@ -1,7 +1,7 @@
 package tripica
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	stdhttp "net/http"
 	"tripica-client/http"
@@ -11,9 +11,9 @@ import (
 )
 
 const (
-	loginBasePathPrivateCustomer = "/api/private/v1/login"
-	loginBasePathAgent    = "/api/private/v1/agent/login"
-	loginBasePathCustomer = "/api/v1/login"
+	loginBasePathPrivateCustomer = "/api/private/{version}/login"
+	loginBasePathAgent    = "/api/private/{version}/agent/login"
+	loginBasePathCustomer = "/api/{version}/login"
 	loginPathGetByCustomerOUID = "/customerOuid/%s"
 	loginPathGenerateJWT       = "/jwt"
 )
@@ -22,16 +22,20 @@ const (
 type loginAPI struct {
 	httpClient      *http.Client
 	address 		string
-	addressAgent    string
-	addressCustomer string
+	endpoints       *endpointRegistry
+	decoder         *decoder
 	logger 			log.Logger
 }
 
 // GetLoginByCustomerOUID retrieves login info using customer OUID.
 func (l *loginAPI) GetLoginByCustomerOUID(customerOUID string) (*Login, error) {
-	url := fmt.Sprintf(l.addressAgent+loginPathGetByCustomerOUID, customerOUID)
+	return l.getLoginByCustomerOUID(context.Background(), customerOUID)
+}
+
+func (l *loginAPI) getLoginByCustomerOUID(ctx context.Context, customerOUID string) (*Login, error) {
+	url := fmt.Sprintf(l.address+l.endpoints.path(EndpointLoginByCustomerOUID), customerOUID)
 
-	resp, err := l.httpClient.Get(url)
+	resp, err := l.httpClient.Get(url, http.WithContext(ctx))
 	if err != nil {
 		return nil, NewTriPicaError(errors.NewHTTPRequestError(err))
 	}
@@ -50,7 +54,9 @@ func (l *loginAPI) GetLoginByCustomerOUID(customerOUID string) (*Login, error) {
 	}
 
 	var multipleLoginResp MultipleLoginResponse
-	if err := json.Unmarshal(resp.Body(), &multipleLoginResp); err != nil {
+	if err := l.decoder.decode(
+		l.endpoints.path(EndpointLoginByCustomerOUID), resp.Body(), &multipleLoginResp,
+	); err != nil {
 		return nil, NewTriPicaError(errors.NewParseError(err, resp.Body()))
 	}
 
@@ -62,7 +68,7 @@ func (l *loginAPI) GetLoginByCustomerOUID(customerOUID string) (*Login, error) {
 }
 
 func (l *loginAPI) GetLoginInfoForToken(token string) (*Login, error) {
-	url := fmt.Sprintf(l.address + loginBasePathPrivateCustomer)
+	url := l.address + l.endpoints.path(EndpointLoginInfo)
 
 	resp, err := l.httpClient.Get(
 		url,
@@ -79,7 +85,7 @@ func (l *loginAPI) GetLoginInfoForToken(token string) (*Login, error) {
 	}
 
 	login := &Login{}
-	if err := json.Unmarshal(resp.Body(), &login); err != nil {
+	if err := l.decoder.decode(l.endpoints.path(EndpointLoginInfo), resp.Body(), &login); err != nil {
 		return nil, errors.NewParseError(err, resp.Body())
 	}
 
@@ -87,7 +93,7 @@ func (l *loginAPI) GetLoginInfoForToken(token string) (*Login, error) {
 }
 
 func (l *loginAPI) authorize(creds Credentials) (*jwt.Token, error) {
-	url := l.addressCustomer + loginPathGenerateJWT
+	url := l.address + l.endpoints.path(EndpointLoginGenerateJWT)
 
 	reqBody := NewTokenRequest(creds.Email, creds.Alias, creds.Password)
 	resp, err := l.httpClient.Post(url, reqBody, http.SkipAuthToken())
@@ -101,7 +107,7 @@ func (l *loginAPI) authorize(creds Credentials) (*jwt.Token, error) {
 	}
 
 	tokenResponse := &TokenResponse{}
-	if err := json.Unmarshal(resp.Body(), &tokenResponse); err != nil {
+	if err := l.decoder.decode(l.endpoints.path(EndpointLoginGenerateJWT), resp.Body(), &tokenResponse); err != nil {
 		return nil, errors.NewParseError(err, resp.Body())
 	}
 
@@ -118,7 +124,8 @@ type MultipleLoginResponse []Login
 
 // Login represents a triPica login.
 type Login struct {
-	Email string `json:"email"`
+	Email      string     `json:"email" tripica:"required"`
+	Extensions Extensions `json:"-"`
 }
 
 // TokenRequest represents a request for a login token within triPica.
@@ -131,7 +138,7 @@ type TokenRequest struct {
 
 // TokenResponse represents a response to a token request.
 type TokenResponse struct {
-	Token string `json:"token"`
+	Token string `json:"token" tripica:"required"`
 }
 
 // NewTokenRequest creates a new token request.
@@ -143,3 +150,17 @@ func NewTokenRequest(email, alias, password string) *TokenRequest {
 		RememberMe: true,
 	}
 }
+
+// UnmarshalJSON decodes the Login, retaining undeclared properties in its Extensions.
+func (l *Login) UnmarshalJSON(data []byte) error {
+	type login Login
+
+	return unmarshalExtended(data, (*login)(l), &l.Extensions)
+}
+
+// MarshalJSON encodes the Login, including its Extensions.
+func (l Login) MarshalJSON() ([]byte, error) {
+	type login Login
+
+	return marshalExtended(login(l), l.Extensions)
+}
@@ -0,0 +1,379 @@
+package tripica
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Possible locales for formatting Money.
+const (
+	LocaleDE = "de-DE"
+	LocaleEN = "en"
+)
+
+// CurrencyEUR is the currency triPica amounts are in, unless stated otherwise.
+const CurrencyEUR = Currency("EUR")
+
+var (
+	errCurrencyMismatch = errors.New("currencies don't match")
+	errMoneyOverflow    = errors.New("amount overflows")
+	errInvalidRatios    = errors.New("allocation ratios need to be positive")
+	errInvalidParts     = errors.New("amounts can only be split into a positive number of parts")
+
+	// currencyExponents lists the number of minor unit digits of currencies which don't have two of them.
+	currencyExponents = map[Currency]int{
+		"BHD": 3,
+		"CLP": 0,
+		"ISK": 0,
+		"JPY": 0,
+		"KRW": 0,
+		"KWD": 3,
+		"OMR": 3,
+		"TND": 3,
+	}
+
+	currencySymbols = map[Currency]string{
+		"EUR": "€",
+		"GBP": "£",
+		"USD": "$",
+	}
+)
+
+type (
+	// Currency represents an ISO 4217 currency code.
+	Currency string
+
+	// Money represents an amount in the minor unit of its currency, e.g. cents.
+	// Arithmetic is only allowed between amounts of the same currency, and fails instead of overflowing.
+	// It is encoded as a bare number of minor units, the way triPica represents amounts.
+	Money struct {
+		amount   int64
+		currency Currency
+	}
+)
+
+// Exponent returns the number of digits of the currency's minor unit.
+func (c Currency) Exponent() int {
+	if e, ok := currencyExponents[c]; ok {
+		return e
+	}
+
+	return 2
+}
+
+// NewMoney returns Money in the minor unit of the currency. An empty currency defaults to EUR.
+func NewMoney(minorUnits int64, currency Currency) Money {
+	if currency == "" {
+		currency = CurrencyEUR
+	}
+
+	return Money{
+		amount:   minorUnits,
+		currency: Currency(strings.ToUpper(string(currency))),
+	}
+}
+
+// EUR returns an amount of euro cents.
+func EUR(cents int64) Money {
+	return NewMoney(cents, CurrencyEUR)
+}
+
+// Amount returns the amount in minor units.
+func (m Money) Amount() int64 {
+	return m.amount
+}
+
+// Currency returns the currency of the amount.
+func (m Money) Currency() Currency {
+	if m.currency == "" {
+		return CurrencyEUR
+	}
+
+	return m.currency
+}
+
+// WithCurrency returns the same amount of minor units in the provided currency.
+func (m Money) WithCurrency(currency Currency) Money {
+	return NewMoney(m.amount, currency)
+}
+
+// IsZero determines whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+// IsPositive determines whether the amount is greater than zero.
+func (m Money) IsPositive() bool {
+	return m.amount > 0
+}
+
+// IsNegative determines whether the amount is less than zero.
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+// Add returns the sum of both amounts.
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+
+	sum := m.amount + other.amount
+	if (other.amount > 0 && sum < m.amount) || (other.amount < 0 && sum > m.amount) {
+		return Money{}, NewTriPicaError(fmt.Errorf("%w: %s + %s", errMoneyOverflow, m, other))
+	}
+
+	return NewMoney(sum, m.Currency()), nil
+}
+
+// Sub returns the difference of both amounts.
+func (m Money) Sub(other Money) (Money, error) {
+	if other.amount == math.MinInt64 {
+		return Money{}, NewTriPicaError(fmt.Errorf("%w: %s - %s", errMoneyOverflow, m, other))
+	}
+
+	return m.Add(other.Negate())
+}
+
+// Multiply returns the amount multiplied by the factor.
+func (m Money) Multiply(factor int64) (Money, error) {
+	if m.amount == 0 || factor == 0 {
+		return NewMoney(0, m.Currency()), nil
+	}
+
+	product := m.amount * factor
+	if product/factor != m.amount || (m.amount == -1 && factor == math.MinInt64) ||
+		(factor == -1 && m.amount == math.MinInt64) {
+		return Money{}, NewTriPicaError(fmt.Errorf("%w: %s * %d", errMoneyOverflow, m, factor))
+	}
+
+	return NewMoney(product, m.Currency()), nil
+}
+
+// Negate returns the amount with the opposite sign.
+func (m Money) Negate() Money {
+	return NewMoney(-m.amount, m.Currency())
+}
+
+// Abs returns the absolute amount.
+func (m Money) Abs() Money {
+	if m.amount < 0 {
+		return m.Negate()
+	}
+
+	return m
+}
+
+// Compare returns -1, 0 or 1 if the amount is less than, equal to or greater than the other one.
+func (m Money) Compare(other Money) (int, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return 0, err
+	}
+
+	switch {
+	case m.amount < other.amount:
+		return -1, nil
+	case m.amount > other.amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Equal determines whether both amounts and currencies are equal.
+func (m Money) Equal(other Money) bool {
+	return m.amount == other.amount && m.Currency() == other.Currency()
+}
+
+// Allocate splits the amount according to the ratios, without losing any minor units.
+// Remaining minor units are distributed one by one, starting with the first share.
+func (m Money) Allocate(ratios ...int) ([]Money, error) {
+	total := 0
+
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, NewTriPicaError(fmt.Errorf("%w: %v", errInvalidRatios, ratios))
+		}
+
+		total += r
+	}
+
+	if total == 0 {
+		return nil, NewTriPicaError(fmt.Errorf("%w: %v", errInvalidRatios, ratios))
+	}
+
+	shares := make([]Money, len(ratios))
+	remainder := m.amount
+
+	for i, r := range ratios {
+		share := m.amount / int64(total) * int64(r)
+		share += m.amount % int64(total) * int64(r) / int64(total)
+		shares[i] = NewMoney(share, m.Currency())
+		remainder -= share
+	}
+
+	unit := int64(1)
+	if remainder < 0 {
+		unit = -1
+	}
+
+	for i := 0; remainder != 0; i = (i + 1) % len(shares) {
+		if ratios[i] == 0 {
+			continue
+		}
+
+		shares[i].amount += unit
+		remainder -= unit
+	}
+
+	return shares, nil
+}
+
+// Split splits the amount into n equal shares, without losing any minor units. n needs to be positive.
+func (m Money) Split(n int) ([]Money, error) {
+	if n <= 0 {
+		return nil, NewTriPicaError(fmt.Errorf("%w: %d", errInvalidParts, n))
+	}
+
+	ratios := make([]int, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+
+	return m.Allocate(ratios...)
+}
+
+// Format formats the amount for the locale, e.g. "1.234,56 €" for LocaleDE and "€1,234.56" for LocaleEN.
+// Unknown locales are formatted like LocaleEN. Currency codes and symbols placed apart from the number
+// are separated from it by a non-breaking space.
+func (m Money) Format(locale string) string {
+	thousands, decimal := ",", "."
+	if strings.HasPrefix(strings.ToLower(locale), "de") {
+		thousands, decimal = ".", ","
+	}
+
+	number := m.formatNumber(thousands, decimal)
+	symbol, ok := currencySymbols[m.Currency()]
+
+	sign := ""
+	if m.amount < 0 {
+		sign = "-"
+	}
+
+	switch {
+	case decimal == ",":
+		if !ok {
+			symbol = string(m.Currency())
+		}
+
+		return sign + number + "\u00a0" + symbol
+	case ok:
+		return sign + symbol + number
+	default:
+		return sign + string(m.Currency()) + "\u00a0" + number
+	}
+}
+
+// String returns the amount in major units followed by the currency code, e.g. "1234.56 EUR".
+func (m Money) String() string {
+	sign := ""
+	if m.amount < 0 {
+		sign = "-"
+	}
+
+	return sign + m.formatNumber("", ".") + " " + string(m.Currency())
+}
+
+// MarshalJSON encodes the amount as a number of minor units.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(m.amount, 10)), nil
+}
+
+// UnmarshalJSON decodes a number of minor units. The currency is set to EUR, unless it was set before.
+func (m *Money) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if bytes.Equal(b, []byte("null")) {
+		m.amount = 0
+
+		return nil
+	}
+
+	var amount int64
+	if err := json.Unmarshal(b, &amount); err != nil {
+		return fmt.Errorf("invalid triPica amount %s: %w", b, err)
+	}
+
+	m.amount = amount
+	m.currency = m.Currency()
+
+	return nil
+}
+
+// SumMoney returns the sum of all amounts, which need to be in the same currency.
+// The sum of no amounts is zero EUR.
+func SumMoney(amounts ...Money) (Money, error) {
+	if len(amounts) == 0 {
+		return EUR(0), nil
+	}
+
+	sum := NewMoney(0, amounts[0].Currency())
+
+	for _, a := range amounts {
+		var err error
+		if sum, err = sum.Add(a); err != nil {
+			return Money{}, err
+		}
+	}
+
+	return sum, nil
+}
+
+// formatNumber formats the absolute amount in major units, using the provided separators.
+func (m Money) formatNumber(thousands, decimal string) string {
+	digits := strconv.FormatUint(absAmount(m.amount), 10)
+	exponent := m.Currency().Exponent()
+
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+
+	integer, fraction := digits[:len(digits)-exponent], digits[len(digits)-exponent:]
+
+	var b strings.Builder
+
+	for i, d := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(thousands)
+		}
+
+		b.WriteRune(d)
+	}
+
+	if exponent > 0 {
+		b.WriteString(decimal)
+		b.WriteString(fraction)
+	}
+
+	return b.String()
+}
+
+func (m Money) sameCurrency(other Money) error {
+	if m.Currency() != other.Currency() {
+		return NewTriPicaError(fmt.Errorf("%w: %s and %s", errCurrencyMismatch, m.Currency(), other.Currency()))
+	}
+
+	return nil
+}
+
+func absAmount(amount int64) uint64 {
+	if amount < 0 {
+		return uint64(-(amount + 1)) + 1
+	}
+
+	return uint64(amount)
+}
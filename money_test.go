@@ -0,0 +1,156 @@
+package tripica
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoney_Arithmetic(t *testing.T) {
+	tests := []struct {
+		name   string
+		op     func() (Money, error)
+		result Money
+		err    error
+	}{
+		{"add", func() (Money, error) { return EUR(150).Add(EUR(-50)) }, EUR(100), nil},
+		{"add overflow", func() (Money, error) { return EUR(math.MaxInt64).Add(EUR(1)) }, Money{}, errMoneyOverflow},
+		{"add underflow", func() (Money, error) { return EUR(math.MinInt64).Add(EUR(-1)) }, Money{}, errMoneyOverflow},
+		{
+			"add currency mismatch",
+			func() (Money, error) { return EUR(1).Add(NewMoney(1, "USD")) }, Money{}, errCurrencyMismatch,
+		},
+		{"sub", func() (Money, error) { return EUR(100).Sub(EUR(150)) }, EUR(-50), nil},
+		{"sub overflow", func() (Money, error) { return EUR(math.MinInt64).Sub(EUR(1)) }, Money{}, errMoneyOverflow},
+		{"sub min int", func() (Money, error) { return EUR(0).Sub(EUR(math.MinInt64)) }, Money{}, errMoneyOverflow},
+		{"multiply", func() (Money, error) { return EUR(-25).Multiply(4) }, EUR(-100), nil},
+		{"multiply by zero", func() (Money, error) { return EUR(math.MaxInt64).Multiply(0) }, EUR(0), nil},
+		{
+			"multiply overflow",
+			func() (Money, error) { return EUR(math.MaxInt64 / 2).Multiply(3) }, Money{}, errMoneyOverflow,
+		},
+		{
+			"multiply min int by -1",
+			func() (Money, error) { return EUR(math.MinInt64).Multiply(-1) }, Money{}, errMoneyOverflow,
+		},
+		{
+			"multiply -1 by min int",
+			func() (Money, error) { return EUR(-1).Multiply(math.MinInt64) }, Money{}, errMoneyOverflow,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.op()
+			if tt.err != nil {
+				assert.True(t, goerrors.Is(err, tt.err), err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.result, result)
+		})
+	}
+}
+
+func TestMoney_Allocate(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount Money
+		ratios []int
+		shares []int64
+		err    bool
+	}{
+		{name: "even", amount: EUR(90), ratios: []int{1, 1, 1}, shares: []int64{30, 30, 30}},
+		{name: "remainder from the first share", amount: EUR(100), ratios: []int{1, 1, 1}, shares: []int64{34, 33, 33}},
+		{name: "negative remainder", amount: EUR(-101), ratios: []int{1, 1, 1}, shares: []int64{-34, -34, -33}},
+		{name: "weighted", amount: EUR(1000), ratios: []int{70, 20, 10}, shares: []int64{700, 200, 100}},
+		{name: "zero ratios get nothing", amount: EUR(5), ratios: []int{0, 1, 1}, shares: []int64{0, 3, 2}},
+		{name: "negative ratio", amount: EUR(5), ratios: []int{-1, 2}, err: true},
+		{name: "only zero ratios", amount: EUR(5), ratios: []int{0, 0}, err: true},
+		{name: "no ratios", amount: EUR(5), err: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			shares, err := tt.amount.Allocate(tt.ratios...)
+			if tt.err {
+				assert.True(t, goerrors.Is(err, errInvalidRatios), err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			amounts := make([]int64, len(shares))
+			for i, share := range shares {
+				amounts[i] = share.Amount()
+				assert.Equal(t, tt.amount.Currency(), share.Currency())
+			}
+
+			assert.Equal(t, tt.shares, amounts)
+
+			sum, err := SumMoney(shares...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.amount, sum)
+		})
+	}
+}
+
+func TestMoney_Split(t *testing.T) {
+	assert := assert.New(t)
+
+	shares, err := NewMoney(10, "JPY").Split(3)
+	assert.NoError(err)
+	assert.Equal([]Money{NewMoney(4, "JPY"), NewMoney(3, "JPY"), NewMoney(3, "JPY")}, shares)
+
+	for _, n := range []int{0, -1} {
+		_, err := EUR(10).Split(n)
+		assert.True(goerrors.Is(err, errInvalidParts), err)
+	}
+}
+
+func TestMoney_Format(t *testing.T) {
+	tests := []struct {
+		amount Money
+		locale string
+		result string
+	}{
+		{EUR(123456), LocaleDE, "1.234,56\u00a0€"},
+		{EUR(123456), LocaleEN, "€1,234.56"},
+		{EUR(-5), LocaleDE, "-0,05\u00a0€"},
+		{EUR(-5), LocaleEN, "-€0.05"},
+		{EUR(100000000), "fr-FR", "€1,000,000.00"},
+		{NewMoney(1234, "CHF"), LocaleDE, "12,34\u00a0CHF"},
+		{NewMoney(1234, "CHF"), LocaleEN, "CHF\u00a012.34"},
+		{NewMoney(1234, "JPY"), LocaleEN, "JPY\u00a01,234"},
+		{NewMoney(1234, "KWD"), LocaleDE, "1,234\u00a0KWD"},
+		{EUR(math.MinInt64), LocaleEN, "-€92,233,720,368,547,758.08"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.result, tt.amount.Format(tt.locale))
+	}
+
+	assert.Equal(t, "-1234.56 EUR", EUR(-123456).String())
+}
+
+func TestBillingAccountBalance_Currency(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var balances []BillingAccountBalance
+	require.NoError(json.Unmarshal([]byte(`[{"amount":100,"currencyCode":"chf"},{"amount":200}]`), &balances))
+
+	assert.Equal(NewMoney(100, "CHF"), balances[0].Amount)
+	assert.Equal(EUR(200), balances[1].Amount)
+}
@@ -0,0 +1,204 @@
+package tripica
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	gohttp "net/http"
+	"strings"
+	"tripica-client/http"
+	"tripica-client/http/errors"
+	"tripica-client/redact"
+)
+
+// Possible codes of field errors detected before sending a request.
+const (
+	FieldErrorRequired = "required"
+	FieldErrorInvalid  = "invalid"
+)
+
+type (
+	// FieldError describes why the value of a single request field was rejected.
+	FieldError struct {
+		Field   string `json:"field"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+
+	// ValidationError is returned if a request was rejected because of invalid values, either by the client
+	// before sending it, in which case StatusCode is 0, or by triPica with 400 Bad Request or 422 Unprocessable Entity.
+	// triPica's validation responses are expected to be of the form
+	//
+	//	{"code": "...", "message": "...", "errors": [{"field": "...", "code": "...", "message": "..."}]}
+	//
+	// Bodies of another form are retained in Message, which is redacted when formatting the error.
+	ValidationError struct {
+		StatusCode int          `json:"-"`
+		Code       string       `json:"code"`
+		Message    string       `json:"message"`
+		Fields     []FieldError `json:"errors"`
+	}
+
+	// VersionConflictError is returned if a resource was modified since the version a request is based on
+	// was retrieved. The resource needs to be retrieved again, and the modification reapplied.
+	VersionConflictError struct {
+		Resource   string
+		OUID       string
+		Version    int
+		StatusCode int
+	}
+
+	// validityEnd represents a request ending the validity of a temporal resource, e.g. a payment mean.
+	// A zero EndDateTime reopens the validity.
+	validityEnd struct {
+		EndDateTime Date `json:"endDateTime"`
+	}
+
+	// mutation describes a request modifying a triPica resource. Conflicts are only reported as
+	// VersionConflictError if the mutation is versioned, i.e. its body carries the version it is based on.
+	mutation struct {
+		method      string
+		url         string
+		endpoint    string
+		body        interface{}
+		resource    string
+		ouid        string
+		version     int
+		versioned   bool
+		description string
+	}
+)
+
+// Error makes ValidationError implement the error interface.
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Fields)+1)
+	if e.Message != "" {
+		parts = append(parts, redact.Default().RedactString(e.Message))
+	}
+
+	for _, f := range e.Fields {
+		parts = append(parts, fmt.Sprintf("%s: %s", f.Field, f.Message))
+	}
+
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("validation failed with status %d: %s", e.StatusCode, strings.Join(parts, "; "))
+	}
+
+	return fmt.Sprintf("validation failed: %s", strings.Join(parts, "; "))
+}
+
+// Field returns the error of the field, or nil if the field wasn't rejected.
+func (e *ValidationError) Field(field string) *FieldError {
+	for i := range e.Fields {
+		if e.Fields[i].Field == field {
+			return &e.Fields[i]
+		}
+	}
+
+	return nil
+}
+
+// Error makes VersionConflictError implement the error interface.
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s %s was modified concurrently, version %d is outdated", e.Resource, e.OUID, e.Version)
+}
+
+// fieldErrors collects field errors, building a ValidationError if there are any.
+type fieldErrors []FieldError
+
+func (f *fieldErrors) require(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		f.add(field, FieldErrorRequired, "is required")
+	}
+}
+
+func (f *fieldErrors) add(field, code, message string) {
+	*f = append(*f, FieldError{Field: field, Code: code, Message: message})
+}
+
+// validateVersion checks the version a modification is based on.
+func validateVersion(errs *fieldErrors, version int) {
+	if version < 0 {
+		errs.add("version", FieldErrorInvalid, "can't be negative")
+	}
+}
+
+func (f fieldErrors) err() error {
+	if len(f) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Fields: f}
+}
+
+// send sends the mutation, decoding the resulting resource into v unless triPica responds without content.
+func (m mutation) send(ctx context.Context, httpClient *http.Client, dec *decoder, v interface{}) error {
+	var (
+		resp *http.Response
+		err  error
+	)
+
+	switch m.method {
+	case gohttp.MethodPost:
+		resp, err = httpClient.Post(m.url, m.body, http.WithContext(ctx), http.JSONContent())
+	case gohttp.MethodPut:
+		resp, err = httpClient.Put(m.url, m.body, http.WithContext(ctx), http.JSONContent())
+	case gohttp.MethodPatch:
+		resp, err = httpClient.Patch(m.url, m.body, http.WithContext(ctx), http.JSONContent())
+	case gohttp.MethodDelete:
+		resp, err = httpClient.Delete(m.url, m.body, http.WithContext(ctx))
+	default:
+		return NewTriPicaError(fmt.Errorf("unsupported mutation method %s", m.method))
+	}
+
+	if err != nil {
+		return NewTriPicaError(errors.NewHTTPRequestError(err))
+	}
+
+	switch status := resp.StatusCode(); {
+	case status == gohttp.StatusOK, status == gohttp.StatusCreated, status == gohttp.StatusAccepted:
+	case status == gohttp.StatusNoContent:
+		return nil
+	case status == gohttp.StatusBadRequest, status == gohttp.StatusUnprocessableEntity:
+		return NewTriPicaError(fmt.Errorf("couldn't %s: %w", m.description, newValidationError(resp)))
+	case m.versioned && (status == gohttp.StatusConflict || status == gohttp.StatusPreconditionFailed):
+		err := &VersionConflictError{
+			Resource:   m.resource,
+			OUID:       m.ouid,
+			Version:    m.version,
+			StatusCode: status,
+		}
+
+		return NewTriPicaError(fmt.Errorf("couldn't %s: %w", m.description, err))
+	default:
+		err := &errors.HTTPError{
+			Body:       string(resp.Body()),
+			StatusCode: status,
+		}
+
+		return NewTriPicaError(fmt.Errorf("couldn't %s: %w", m.description, err))
+	}
+
+	if v == nil || len(resp.Body()) == 0 {
+		return nil
+	}
+
+	if err := dec.decode(m.endpoint, resp.Body(), v); err != nil {
+		return NewTriPicaError(errors.NewParseError(err, resp.Body()))
+	}
+
+	return nil
+}
+
+// newValidationError maps a validation response of triPica to a ValidationError.
+func newValidationError(resp *http.Response) *ValidationError {
+	validationErr := &ValidationError{}
+	if err := json.Unmarshal(resp.Body(), validationErr); err != nil ||
+		(validationErr.Message == "" && len(validationErr.Fields) == 0) {
+		validationErr = &ValidationError{Message: strings.TrimSpace(string(resp.Body()))}
+	}
+
+	validationErr.StatusCode = resp.StatusCode()
+
+	return validationErr
+}
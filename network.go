@@ -1,7 +1,6 @@
 package tripica
 
 import (
-	"encoding/json"
 	"fmt"
 	gohttp "net/http"
 
@@ -11,7 +10,7 @@ import (
 )
 
 const (
-	networkEntityBasePath = "/api/private/v1/agent/networkEntity"
+	networkEntityBasePath = "/api/private/{version}/agent/networkEntity"
 
 	networkEntityPathGetNetworkEntityBySubscriptionOuid = "/subscriptionOuid/%s"
 )
@@ -19,13 +18,15 @@ const (
 type networkEntityAPI struct {
 	httpClient *http.Client
 	address    string
+	endpoints  *endpointRegistry
+	decoder    *decoder
 
 	logger log.Logger
 }
 
 // GetNetworkEntityForSubscriptionOuid returns a NetworkEntity by subscriptionOuid.
 func (e *networkEntityAPI) GetNetworkEntityBySubscriptionOuid(subscriptionOuid string) (*NetworkEntity, error) {
-	url := fmt.Sprintf(e.address+networkEntityPathGetNetworkEntityBySubscriptionOuid, subscriptionOuid)
+	url := fmt.Sprintf(e.address+e.endpoints.path(EndpointNetworkEntityBySubscriptionOUID), subscriptionOuid)
 
 	resp, err := e.httpClient.Get(url)
 	if err != nil {
@@ -50,7 +51,9 @@ func (e *networkEntityAPI) GetNetworkEntityBySubscriptionOuid(subscriptionOuid s
 	}
 
 	var networkEntity *NetworkEntity
-	if err := json.Unmarshal(resp.Body(), &networkEntity); err != nil {
+	if err := e.decoder.decode(
+		e.endpoints.path(EndpointNetworkEntityBySubscriptionOUID), resp.Body(), &networkEntity,
+	); err != nil {
 		return nil, NewTriPicaError(errors.NewParseError(err, resp.Body()))
 	}
 
@@ -86,18 +89,63 @@ func (e *networkEntityAPI) GetMeterNumbersForProducts(products []Product) ([]str
 
 // NetworkEntityDTO represents a triPica network entity.
 type NetworkEntity struct {
-	OUID             string              `json:"ouid"`
+	OUID             string              `json:"ouid" tripica:"required"`
 	Type             string              `json:"type"`
 	SubscriptionOUID string              `json:"subscriptionOuid"`
-	NetworkItems     []NetworkEntityItem `json:"networkItem"`
+	NetworkItems     []NetworkEntityItem `json:"networkItem" tripica:"required"`
+	Extensions       Extensions          `json:"-"`
 }
 
 // NetworkEntityItem represents a triPica network entity item.
 type NetworkEntityItem struct {
 	Characteristics NetworkEntityItemCharacteristics `json:"characteristics"`
+	Extensions      Extensions                       `json:"-"`
 }
 
 // NetworkEntityItemCharacteristics contains characterics part of NetworkEntityItem.
 type NetworkEntityItemCharacteristics struct {
-	MeterNumber string `json:"meterData.meterNumber"`
+	MeterNumber string     `json:"meterData.meterNumber"`
+	Extensions  Extensions `json:"-"`
+}
+
+// UnmarshalJSON decodes the NetworkEntity, retaining undeclared properties in its Extensions.
+func (e *NetworkEntity) UnmarshalJSON(data []byte) error {
+	type networkEntity NetworkEntity
+
+	return unmarshalExtended(data, (*networkEntity)(e), &e.Extensions)
+}
+
+// MarshalJSON encodes the NetworkEntity, including its Extensions.
+func (e NetworkEntity) MarshalJSON() ([]byte, error) {
+	type networkEntity NetworkEntity
+
+	return marshalExtended(networkEntity(e), e.Extensions)
+}
+
+// UnmarshalJSON decodes the NetworkEntityItem, retaining undeclared properties in its Extensions.
+func (i *NetworkEntityItem) UnmarshalJSON(data []byte) error {
+	type networkEntityItem NetworkEntityItem
+
+	return unmarshalExtended(data, (*networkEntityItem)(i), &i.Extensions)
+}
+
+// MarshalJSON encodes the NetworkEntityItem, including its Extensions.
+func (i NetworkEntityItem) MarshalJSON() ([]byte, error) {
+	type networkEntityItem NetworkEntityItem
+
+	return marshalExtended(networkEntityItem(i), i.Extensions)
+}
+
+// UnmarshalJSON decodes the NetworkEntityItemCharacteristics, retaining undeclared properties in its Extensions.
+func (c *NetworkEntityItemCharacteristics) UnmarshalJSON(data []byte) error {
+	type networkEntityItemCharacteristics NetworkEntityItemCharacteristics
+
+	return unmarshalExtended(data, (*networkEntityItemCharacteristics)(c), &c.Extensions)
+}
+
+// MarshalJSON encodes the NetworkEntityItemCharacteristics, including its Extensions.
+func (c NetworkEntityItemCharacteristics) MarshalJSON() ([]byte, error) {
+	type networkEntityItemCharacteristics NetworkEntityItemCharacteristics
+
+	return marshalExtended(networkEntityItemCharacteristics(c), c.Extensions)
 }
@@ -8,6 +8,8 @@ import (
 )
 
 const (
+	notifyBasePath = ""
+
 	notifyPathSendNotification = "/notif"
 	notifyPathSendTermination  = "/terminate"
 	// Listing of possible event names.
@@ -19,6 +21,7 @@ const (
 type notifyAPI struct {
 	httpClient *http.Client
 	address    string
+	endpoints  *endpointRegistry
 }
 
 // Notify notifies triPica about certain event.
@@ -27,9 +30,9 @@ func (n *notifyAPI) Notify(req *NotifyRequest) error {
 
 	switch req.EventName {
 	case NotifSentEventName:
-		url = fmt.Sprintf(n.address + notifyPathSendNotification)
+		url = n.address + n.endpoints.path(EndpointNotifySendNotification)
 	case TerminateContractEventName:
-		url = fmt.Sprintf(n.address + notifyPathSendTermination)
+		url = n.address + n.endpoints.path(EndpointNotifySendTermination)
 	default:
 		return NewTriPicaError(fmt.Errorf("unknown event name in NotifyRequest: %s", req.EventName))
 	}
@@ -59,7 +62,7 @@ type NotifyRequest struct {
 	EventName       string            `json:"eventName"`
 	CaseExternalID  string            `json:"caseExternalId"`
 	EventExternalID string            `json:"eventExternalId"`
-	EventDate       int               `json:"eventDate"`
+	EventDate       Date              `json:"eventDate"`
 	Status          string            `json:"status"`
 	Comment         string            `json:"comment"`
 	Attachments     []Attachment      `json:"attachments,omitempty"`
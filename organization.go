@@ -0,0 +1,187 @@
+package tripica
+
+import (
+	"context"
+	"fmt"
+	gohttp "net/http"
+	"strings"
+	"time"
+	"tripica-client/http"
+	"tripica-client/http/errors"
+	"tripica-client/log"
+)
+
+const (
+	organizationBasePath = "/api/private/{version}/agent/organization"
+
+	organizationPathGetByPartyOUID = "/%s"
+)
+
+// Organization manages organization related endpoints within triPica.
+type organizationAPI struct {
+	httpClient *http.Client
+	address    string
+	endpoints  *endpointRegistry
+	decoder    *decoder
+
+	logger log.Logger
+}
+
+// GetOrganizationByPartyOUID retrieves an organization by the customer's party OUID.
+func (o *organizationAPI) GetOrganizationByPartyOUID(partyOUID string) (*Organization, error) {
+	return o.getOrganizationByPartyOUID(context.Background(), partyOUID)
+}
+
+func (o *organizationAPI) getOrganizationByPartyOUID(ctx context.Context, partyOUID string) (*Organization, error) {
+	url := fmt.Sprintf(o.address+o.endpoints.path(EndpointOrganizationByPartyOUID), partyOUID)
+
+	resp, err := o.httpClient.Get(url, http.WithContext(ctx))
+	if err != nil {
+		return nil, NewTriPicaError(errors.NewHTTPRequestError(err))
+	}
+
+	if resp.StatusCode() == gohttp.StatusNoContent {
+		return nil, nil
+	}
+
+	if resp.StatusCode() != gohttp.StatusOK {
+		err := &errors.HTTPError{
+			Body:       string(resp.Body()),
+			StatusCode: resp.StatusCode(),
+		}
+
+		return nil, NewTriPicaError(fmt.Errorf("couldn't retrieve organization with partyOUID %s: %w", partyOUID, err))
+	}
+
+	var organization *Organization
+	if err := o.decoder.decode(o.endpoints.path(EndpointOrganizationByPartyOUID), resp.Body(), &organization); err != nil {
+		return nil, NewTriPicaError(errors.NewParseError(err, resp.Body()))
+	}
+
+	return organization, nil
+}
+
+// Organization represents a triPica organization, i.e. the party of a business customer. Its contact mediums
+// are the ones of the organization itself, e.g. its registered office, while the contact persons have their own.
+type Organization struct {
+	OUID               string          `json:"ouid" tripica:"required"`
+	Version            int             `json:"version"`
+	Name               string          `json:"name" tripica:"required"`
+	TradingName        string          `json:"tradingName"`
+	LegalForm          string          `json:"legalForm"`
+	RegistrationNumber string          `json:"registrationNumber"`
+	RegistrationCourt  string          `json:"registrationCourt"`
+	VATID              string          `json:"vatId"`
+	ContactMediums     []ContactMedium `json:"contactMediums"`
+	ContactPersons     []ContactPerson `json:"contactPersons"`
+	Extensions         Extensions      `json:"-"`
+}
+
+// ContactPerson represents a person acting on behalf of an organization, e.g. its accountant.
+type ContactPerson struct {
+	PartyOUID      string          `json:"partyOuid"`
+	Title          string          `json:"title"`
+	Name           string          `json:"givenName"`
+	LastName       string          `json:"familyName"`
+	Role           string          `json:"role"`
+	Preferred      bool            `json:"prefered"`
+	ContactMediums []ContactMedium `json:"contactMediums"`
+	Extensions     Extensions      `json:"-"`
+}
+
+// DisplayName returns the trading name of the organization, falling back to its registered name.
+func (o *Organization) DisplayName() string {
+	if o.TradingName != "" {
+		return o.TradingName
+	}
+
+	return o.Name
+}
+
+// ContactPerson returns the preferred contact person, falling back to the first one, or nil if there is none.
+func (o *Organization) ContactPerson() *ContactPerson {
+	for i := range o.ContactPersons {
+		if o.ContactPersons[i].Preferred {
+			return &o.ContactPersons[i]
+		}
+	}
+
+	if len(o.ContactPersons) == 0 {
+		return nil
+	}
+
+	return &o.ContactPersons[0]
+}
+
+// DeliveryAddress returns the delivery address medium of the organization, see Individual.DeliveryAddress.
+func (o *Organization) DeliveryAddress(now time.Time) MediumTypeAddress {
+	return o.mediums().DeliveryAddress(now)
+}
+
+// BillingAddress returns the billing address medium of the organization, see Individual.BillingAddress.
+func (o *Organization) BillingAddress(now time.Time) MediumTypeAddress {
+	return o.mediums().BillingAddress(now)
+}
+
+// PrimaryEmail returns the email address of the organization, see Individual.PrimaryEmail.
+func (o *Organization) PrimaryEmail(now time.Time) string {
+	return o.mediums().PrimaryEmail(now)
+}
+
+// PrimaryPhone returns the phone number of the organization, see Individual.PrimaryPhone.
+func (o *Organization) PrimaryPhone(now time.Time) string {
+	return o.mediums().PrimaryPhone(now)
+}
+
+// Timeline returns the timeline of the organization's contact mediums of the type, see Individual.Timeline.
+func (o *Organization) Timeline(typ ContactMediumType) *ContactMediumTimeline {
+	return o.mediums().Timeline(typ)
+}
+
+// mediums returns an individual with the organization's contact mediums, which offers their lookups.
+func (o *Organization) mediums() *Individual {
+	return &Individual{ContactMediums: o.ContactMediums}
+}
+
+// FullName returns the title and names of the contact person, separated by spaces.
+func (p *ContactPerson) FullName() string {
+	return strings.Join(strings.Fields(strings.Join([]string{p.Title, p.Name, p.LastName}, " ")), " ")
+}
+
+// PrimaryEmail returns the email address of the contact person, see Individual.PrimaryEmail.
+func (p *ContactPerson) PrimaryEmail(now time.Time) string {
+	return (&Individual{ContactMediums: p.ContactMediums}).PrimaryEmail(now)
+}
+
+// PrimaryPhone returns the phone number of the contact person, see Individual.PrimaryPhone.
+func (p *ContactPerson) PrimaryPhone(now time.Time) string {
+	return (&Individual{ContactMediums: p.ContactMediums}).PrimaryPhone(now)
+}
+
+// UnmarshalJSON decodes the Organization, retaining undeclared properties in its Extensions.
+func (o *Organization) UnmarshalJSON(data []byte) error {
+	type organization Organization
+
+	return unmarshalExtended(data, (*organization)(o), &o.Extensions)
+}
+
+// MarshalJSON encodes the Organization, including its Extensions.
+func (o Organization) MarshalJSON() ([]byte, error) {
+	type organization Organization
+
+	return marshalExtended(organization(o), o.Extensions)
+}
+
+// UnmarshalJSON decodes the ContactPerson, retaining undeclared properties in its Extensions.
+func (p *ContactPerson) UnmarshalJSON(data []byte) error {
+	type contactPerson ContactPerson
+
+	return unmarshalExtended(data, (*contactPerson)(p), &p.Extensions)
+}
+
+// MarshalJSON encodes the ContactPerson, including its Extensions.
+func (p ContactPerson) MarshalJSON() ([]byte, error) {
+	type contactPerson ContactPerson
+
+	return marshalExtended(contactPerson(p), p.Extensions)
+}
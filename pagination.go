@@ -0,0 +1,175 @@
+package tripica
+
+import (
+	"context"
+	"fmt"
+	gohttp "net/http"
+	"strconv"
+	"tripica-client/http"
+	"tripica-client/http/errors"
+)
+
+const (
+	paginationParamOffset = "offset"
+	paginationParamLimit  = "limit"
+
+	// DefaultPageSize is the number of elements fetched per page, unless specified otherwise.
+	DefaultPageSize = 100
+)
+
+type (
+	// Iterator lazily fetches the pages of a triPica list endpoint.
+	// A new page is only requested once all elements of the previous one were consumed.
+	//
+	//	it := client.IterateProductsByCustomerOUID(ctx, customerOUID, nil, 0)
+	//	for it.Next() {
+	//		product := it.Value()
+	//	}
+	//	if err := it.Err(); err != nil {
+	//		...
+	//	}
+	Iterator[T any] struct {
+		ctx      context.Context
+		fetch    pageFetcher[T]
+		pageSize int
+		offset   int
+		page     []T
+		index    int
+		last     bool
+		err      error
+	}
+
+	// pageFetcher fetches limit elements, starting at offset.
+	pageFetcher[T any] func(ctx context.Context, offset, limit int) ([]T, error)
+)
+
+// newIterator returns an Iterator fetching pages of the provided size. Non-positive sizes fall back to DefaultPageSize.
+func newIterator[T any](ctx context.Context, pageSize int, fetch pageFetcher[T]) *Iterator[T] {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	return &Iterator[T]{
+		ctx:      ctx,
+		fetch:    fetch,
+		pageSize: pageSize,
+		index:    -1,
+	}
+}
+
+// Next advances the iterator to the next element, fetching a new page if necessary.
+// It returns false once all elements were consumed, or an error occurred.
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	if it.index+1 < len(it.page) {
+		it.index++
+
+		return true
+	}
+
+	if it.last {
+		return false
+	}
+
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+
+		return false
+	}
+
+	page, err := it.fetch(it.ctx, it.offset, it.pageSize)
+	if err != nil {
+		it.err = err
+
+		return false
+	}
+
+	it.page = page
+	it.index = 0
+	it.offset += len(page)
+	it.last = len(page) < it.pageSize
+
+	return len(page) > 0
+}
+
+// Value returns the current element.
+func (it *Iterator[T]) Value() T {
+	return it.page[it.index]
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// ForEach calls fn for every remaining element, stopping at the first error.
+func (it *Iterator[T]) ForEach(fn func(T) error) error {
+	for it.Next() {
+		if err := fn(it.Value()); err != nil {
+			return err
+		}
+	}
+
+	return it.Err()
+}
+
+// Collect consumes the iterator, returning all remaining elements.
+func (it *Iterator[T]) Collect() ([]T, error) {
+	elements := []T{}
+
+	for it.Next() {
+		elements = append(elements, it.Value())
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return elements, nil
+}
+
+// getPage fetches a single page of a list endpoint. A response without content is treated as an empty page.
+// The endpoint is the path template of the URL, used to report schema drift.
+func getPage[T any](
+	ctx context.Context,
+	httpClient *http.Client,
+	dec *decoder,
+	url, endpoint string,
+	offset, limit int,
+	description string,
+) ([]T, error) {
+	resp, err := httpClient.Get(
+		url,
+		http.WithContext(ctx),
+		http.QueryParams(map[string]string{
+			paginationParamOffset: strconv.Itoa(offset),
+			paginationParamLimit:  strconv.Itoa(limit),
+		}),
+	)
+	if err != nil {
+		return nil, NewTriPicaError(errors.NewHTTPRequestError(err))
+	}
+
+	if resp.StatusCode() == gohttp.StatusNoContent {
+		return []T{}, nil
+	}
+
+	if resp.StatusCode() != gohttp.StatusOK {
+		err := &errors.HTTPError{
+			Body:       string(resp.Body()),
+			StatusCode: resp.StatusCode(),
+		}
+
+		return nil, NewTriPicaError(fmt.Errorf("couldn't retrieve %s: %w", description, err))
+	}
+
+	var page []T
+	if err := dec.decode(endpoint, resp.Body(), &page); err != nil {
+		return nil, NewTriPicaError(errors.NewParseError(err, resp.Body()))
+	}
+
+	return page, nil
+}
@@ -0,0 +1,209 @@
+package tripica
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	gohttp "net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const paginationProductsPath = "/api/private/v1/agent/product/customerOuid/C1"
+
+// pages returns a pageFetcher serving the elements 0 to n-1, recording the offset of each fetch.
+func pages(n int, offsets *[]int) pageFetcher[int] {
+	return func(ctx context.Context, offset, limit int) ([]int, error) {
+		*offsets = append(*offsets, offset)
+
+		page := []int{}
+		for i := offset; i < n && i < offset+limit; i++ {
+			page = append(page, i)
+		}
+
+		return page, nil
+	}
+}
+
+func TestIterator(t *testing.T) {
+	tests := []struct {
+		name     string
+		elements int
+		pageSize int
+		offsets  []int
+	}{
+		{name: "empty", elements: 0, pageSize: 3, offsets: []int{0}},
+		{name: "single page", elements: 2, pageSize: 3, offsets: []int{0}},
+		{name: "last page is partial", elements: 7, pageSize: 3, offsets: []int{0, 3, 6}},
+		{name: "last page is full", elements: 6, pageSize: 3, offsets: []int{0, 3, 6}},
+		{name: "default page size", elements: 150, pageSize: 0, offsets: []int{0, DefaultPageSize}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			var offsets []int
+
+			elements, err := newIterator(context.Background(), tt.pageSize, pages(tt.elements, &offsets)).Collect()
+
+			require.NoError(t, err)
+			assert.Len(t, elements, tt.elements)
+
+			for i, element := range elements {
+				assert.Equal(t, i, element)
+			}
+
+			assert.Equal(t, tt.offsets, offsets)
+		})
+	}
+}
+
+func TestIterator_Lazy(t *testing.T) {
+	assert := assert.New(t)
+
+	var offsets []int
+
+	it := newIterator(context.Background(), 3, pages(7, &offsets))
+
+	for i := 0; i < 3; i++ {
+		require.True(t, it.Next())
+		assert.Equal(i, it.Value())
+	}
+
+	assert.Equal([]int{0}, offsets, "the next page isn't fetched before it is needed")
+
+	require.True(t, it.Next())
+	assert.Equal(3, it.Value())
+	assert.Equal([]int{0, 3}, offsets)
+}
+
+func TestIterator_Errors(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("fetch errors stop the iteration", func(t *testing.T) {
+		errFetch := goerrors.New("fetch failed")
+		fetches := 0
+
+		it := newIterator(context.Background(), 2, func(ctx context.Context, offset, limit int) ([]int, error) {
+			fetches++
+			if offset > 0 {
+				return nil, errFetch
+			}
+
+			return []int{0, 1}, nil
+		})
+
+		elements, err := it.Collect()
+
+		assert.True(goerrors.Is(err, errFetch))
+		assert.Nil(elements)
+		assert.False(it.Next())
+		assert.Equal(2, fetches, "failed fetches aren't retried")
+	})
+
+	t.Run("cancelled contexts stop the iteration before the next page", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var offsets []int
+
+		it := newIterator(ctx, 2, pages(5, &offsets))
+
+		require.True(t, it.Next())
+		cancel()
+		require.True(t, it.Next(), "elements of the current page are still returned")
+
+		assert.False(it.Next())
+		assert.True(goerrors.Is(it.Err(), context.Canceled))
+		assert.Equal([]int{0}, offsets)
+	})
+}
+
+func TestIterator_ForEach(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("all elements are visited", func(t *testing.T) {
+		var offsets, visited []int
+
+		err := newIterator(context.Background(), 2, pages(5, &offsets)).ForEach(func(i int) error {
+			visited = append(visited, i)
+
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal([]int{0, 1, 2, 3, 4}, visited)
+	})
+
+	t.Run("errors of fn stop the iteration", func(t *testing.T) {
+		var offsets, visited []int
+
+		errStop := goerrors.New("stop")
+
+		err := newIterator(context.Background(), 2, pages(5, &offsets)).ForEach(func(i int) error {
+			visited = append(visited, i)
+			if i == 2 {
+				return errStop
+			}
+
+			return nil
+		})
+
+		assert.True(goerrors.Is(err, errStop))
+		assert.Equal([]int{0, 1, 2}, visited)
+		assert.Equal([]int{0, 2}, offsets)
+	})
+}
+
+func TestProductAPI_IterateProductsByCustomerOUID(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("pages are requested with offset and limit", func(t *testing.T) {
+		var queries []string
+
+		client := newTestClient(t, gohttp.HandlerFunc(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+			require.Equal(t, paginationProductsPath, r.URL.Path)
+			queries = append(queries, r.URL.RawQuery)
+
+			if r.URL.Query().Get(paginationParamOffset) == "0" {
+				_, _ = w.Write([]byte(`[{"ouid":"A"},{"ouid":"B"}]`))
+
+				return
+			}
+
+			_, _ = w.Write([]byte(`[{"ouid":"C"}]`))
+		}), nil)
+
+		products, err := client.IterateProductsByCustomerOUID(
+			context.Background(), "C1", NewFilter().Status("ACTIVE"), 2,
+		).Collect()
+
+		require.NoError(t, err)
+		require.Len(t, products, 3)
+		assert.Equal("C", products[2].OUID)
+
+		filter, err := NewFilter().Status("ACTIVE").Encode()
+		require.NoError(t, err)
+		assert.Equal([]string{
+			fmt.Sprintf("filters=%s&limit=2&offset=0", filter),
+			fmt.Sprintf("filters=%s&limit=2&offset=2", filter),
+		}, queries)
+	})
+
+	t.Run("invalid filters are rejected without requests", func(t *testing.T) {
+		requests := 0
+
+		client := newTestClient(t, gohttp.HandlerFunc(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+			requests++
+		}), nil)
+
+		products, err := client.IterateProductsByCustomerOUID(context.Background(), "C1", NewFilter().Status(), 2).Collect()
+
+		assert.Error(err)
+		assert.Nil(products)
+		assert.Zero(requests)
+	})
+}
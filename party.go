@@ -0,0 +1,116 @@
+package tripica
+
+import (
+	"context"
+	goerrors "errors"
+	gohttp "net/http"
+	"strings"
+	"time"
+	"tripica-client/http/errors"
+)
+
+// Possible types of parties referenced by customers.
+const (
+	PartyTypeIndividual   PartyType = "INDIVIDUAL"
+	PartyTypeOrganization PartyType = "ORGANIZATION"
+)
+
+type (
+	// PartyType represents the type of party a customer belongs to.
+	PartyType string
+
+	// Party represents the party a customer belongs to, which is either an *Individual or an *Organization.
+	// It offers the lookups common to both, while their specific attributes require a type switch:
+	//
+	//	switch p := party.(type) {
+	//	case *tripica.Individual:
+	//		...
+	//	case *tripica.Organization:
+	//		...
+	//	}
+	Party interface {
+		PartyType() PartyType
+		DisplayName() string
+		Recipient() Recipient
+		DeliveryAddress(now time.Time) MediumTypeAddress
+		BillingAddress(now time.Time) MediumTypeAddress
+		PrimaryEmail(now time.Time) string
+		Timeline(typ ContactMediumType) *ContactMediumTimeline
+	}
+)
+
+// PartyType makes Individual implement Party.
+func (i *Individual) PartyType() PartyType {
+	return PartyTypeIndividual
+}
+
+// DisplayName returns the title and names of the individual, separated by spaces.
+func (i *Individual) DisplayName() string {
+	return strings.Join(strings.Fields(strings.Join([]string{i.Title, i.Name, i.LastName}, " ")), " ")
+}
+
+// PartyType makes Organization implement Party.
+func (o *Organization) PartyType() PartyType {
+	return PartyTypeOrganization
+}
+
+// ResolveParty retrieves the party the reference points to. Since the reference doesn't specify the party's type,
+// the party is looked up as an individual first, and as an organization if there is no such individual;
+// if neither exists, no party and no error are returned.
+func (c *Client) ResolveParty(ctx context.Context, ref PartyRef) (Party, error) {
+	if ref.PartyOUID == "" {
+		return nil, NewTriPicaError(errNoPartyRef)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, NewTriPicaError(err)
+	}
+
+	party, err := c.resolveIndividual(ctx, ref.PartyOUID)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	if party != nil {
+		return party, nil
+	}
+
+	party, err = c.resolveOrganization(ctx, ref.PartyOUID)
+	if err != nil && isNotFound(err) {
+		return nil, nil
+	}
+
+	return party, err
+}
+
+// ResolveCustomerParty retrieves the party the customer belongs to, see ResolveParty.
+func (c *Client) ResolveCustomerParty(ctx context.Context, customer *Customer) (Party, error) {
+	return c.ResolveParty(ctx, customer.PartyRef)
+}
+
+// resolveIndividual retrieves the individual, avoiding a non-nil Party wrapping a nil *Individual.
+func (c *Client) resolveIndividual(ctx context.Context, partyOUID string) (Party, error) {
+	individual, err := c.getIndividualByPartyOUID(ctx, partyOUID)
+	if err != nil || individual == nil {
+		return nil, err
+	}
+
+	return individual, nil
+}
+
+// resolveOrganization retrieves the organization, avoiding a non-nil Party wrapping a nil *Organization.
+func (c *Client) resolveOrganization(ctx context.Context, partyOUID string) (Party, error) {
+	organization, err := c.getOrganizationByPartyOUID(ctx, partyOUID)
+	if err != nil || organization == nil {
+		return nil, err
+	}
+
+	return organization, nil
+}
+
+// isNotFound determines whether triPica responded with 404 Not Found.
+func isNotFound(err error) bool {
+	var httpErr *errors.HTTPError
+
+	return goerrors.As(err, &httpErr) && httpErr.StatusCode == gohttp.StatusNotFound
+}
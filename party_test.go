@@ -0,0 +1,213 @@
+package tripica
+
+import (
+	"context"
+	goerrors "errors"
+	gohttp "net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	partyIndividualPath   = "/api/private/v1/agent/individual/P1"
+	partyOrganizationPath = "/api/private/v1/agent/organization/P1"
+)
+
+// partyServer answers the lookups of the party P1 with the configured status codes, recording the requested paths.
+type partyServer struct {
+	mux          sync.Mutex
+	individual   int
+	organization int
+	requested    []string
+}
+
+func (s *partyServer) ServeHTTP(w gohttp.ResponseWriter, r *gohttp.Request) {
+	s.mux.Lock()
+	s.requested = append(s.requested, r.URL.Path)
+	s.mux.Unlock()
+
+	switch r.URL.Path {
+	case partyIndividualPath:
+		w.WriteHeader(s.individual)
+
+		if s.individual == gohttp.StatusOK {
+			_, _ = w.Write([]byte(`{"ouid":"P1","givenName":"Max","familyName":"Mustermann"}`))
+		}
+	case partyOrganizationPath:
+		w.WriteHeader(s.organization)
+
+		if s.organization == gohttp.StatusOK {
+			_, _ = w.Write([]byte(`{"ouid":"P1","name":"Muster GmbH","tradingName":"Muster","contactMediums":[
+				{"type":"DELIVERY_ADDRESS","startDateTime":1500000000000,"medium":{"city":"Berlin"}},
+				{"type":"BILLING_ADDRESS","startDateTime":1500000000000,"medium":{"city":"Hamburg"}},
+				{"type":"PRIMARY","startDateTime":1500000000000,"medium":{"type":"EMAIL","emailAddress":"info@muster.de"}}
+			]}`))
+		}
+	default:
+		w.WriteHeader(gohttp.StatusTeapot)
+	}
+}
+
+func TestClient_ResolveParty(t *testing.T) {
+	tests := []struct {
+		name         string
+		individual   int
+		organization int
+		want         PartyType
+		wantErr      bool
+		requested    []string
+	}{
+		{
+			name:       "individuals are looked up first",
+			individual: gohttp.StatusOK,
+			want:       PartyTypeIndividual,
+			requested:  []string{partyIndividualPath},
+		},
+		{
+			name:         "organizations are looked up if there is no such individual",
+			individual:   gohttp.StatusNotFound,
+			organization: gohttp.StatusOK,
+			want:         PartyTypeOrganization,
+			requested:    []string{partyIndividualPath, partyOrganizationPath},
+		},
+		{
+			name:         "organizations are looked up if the individual has no content",
+			individual:   gohttp.StatusNoContent,
+			organization: gohttp.StatusOK,
+			want:         PartyTypeOrganization,
+			requested:    []string{partyIndividualPath, partyOrganizationPath},
+		},
+		{
+			name:         "neither exists",
+			individual:   gohttp.StatusNotFound,
+			organization: gohttp.StatusNotFound,
+			requested:    []string{partyIndividualPath, partyOrganizationPath},
+		},
+		{
+			name:         "neither has content",
+			individual:   gohttp.StatusNoContent,
+			organization: gohttp.StatusNoContent,
+			requested:    []string{partyIndividualPath, partyOrganizationPath},
+		},
+		{
+			name:       "individual errors aren't masked by the organization lookup",
+			individual: gohttp.StatusInternalServerError,
+			wantErr:    true,
+			requested:  []string{partyIndividualPath},
+		},
+		{
+			name:         "organization errors are returned",
+			individual:   gohttp.StatusNotFound,
+			organization: gohttp.StatusInternalServerError,
+			wantErr:      true,
+			requested:    []string{partyIndividualPath, partyOrganizationPath},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &partyServer{individual: tt.individual, organization: tt.organization}
+			client := newTestClient(t, srv, nil)
+
+			party, err := client.ResolveParty(context.Background(), PartyRef{PartyOUID: "P1"})
+
+			assert.Equal(t, tt.requested, srv.requested)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.True(t, party == nil, "no typed nil is returned")
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			if tt.want == "" {
+				assert.True(t, party == nil, "no typed nil is returned")
+
+				return
+			}
+
+			require.NotNil(t, party)
+			assert.Equal(t, tt.want, party.PartyType())
+		})
+	}
+}
+
+func TestClient_ResolveParty_Attributes(t *testing.T) {
+	assert := assert.New(t)
+
+	client := newTestClient(t, &partyServer{
+		individual:   gohttp.StatusNotFound,
+		organization: gohttp.StatusOK,
+	}, nil)
+
+	party, err := client.ResolveCustomerParty(context.Background(), &Customer{PartyRef: PartyRef{PartyOUID: "P1"}})
+
+	require.NoError(t, err)
+
+	organization, ok := party.(*Organization)
+	require.True(t, ok)
+	assert.Equal("Muster GmbH", organization.Name)
+	assert.Equal("Muster", party.DisplayName())
+
+	now := time.UnixMilli(1600000000000)
+	assert.Equal("Berlin", party.DeliveryAddress(now).City)
+	assert.Equal("Hamburg", party.BillingAddress(now).City)
+	assert.Equal("info@muster.de", party.PrimaryEmail(now))
+	assert.Len(party.Timeline(ContactMediumTypeDeliveryAddress).History(), 1)
+}
+
+func TestClient_ResolveParty_Errors(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("a party OUID is required", func(t *testing.T) {
+		srv := &partyServer{}
+		client := newTestClient(t, srv, nil)
+
+		party, err := client.ResolveParty(context.Background(), PartyRef{})
+
+		assert.True(goerrors.Is(err, errNoPartyRef))
+		assert.Nil(party)
+		assert.Empty(srv.requested)
+	})
+
+	t.Run("cancelled contexts are respected", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		srv := &partyServer{}
+		client := newTestClient(t, srv, nil)
+
+		party, err := client.ResolveParty(ctx, PartyRef{PartyOUID: "P1"})
+
+		assert.True(goerrors.Is(err, context.Canceled))
+		assert.Nil(party)
+		assert.Empty(srv.requested)
+	})
+	t.Run("lookups are cancelled with the context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var requested []string
+
+		client := newTestClient(t, gohttp.HandlerFunc(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+			requested = append(requested, r.URL.Path)
+
+			cancel()
+			<-r.Context().Done()
+		}), nil)
+
+		party, err := client.ResolveParty(ctx, PartyRef{PartyOUID: "P1"})
+
+		assert.True(goerrors.Is(err, context.Canceled))
+		assert.Nil(party)
+		assert.Equal([]string{partyIndividualPath}, requested, "organizations aren't looked up")
+	})
+}
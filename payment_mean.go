@@ -0,0 +1,322 @@
+package tripica
+
+import (
+	"context"
+	"fmt"
+	gohttp "net/http"
+	"strings"
+	"time"
+	"tripica-client/http"
+	"tripica-client/http/errors"
+	"tripica-client/iban"
+)
+
+const (
+	customerPathPaymentMeans = "/%s/paymentMean"
+	customerPathPaymentMean  = "/%s/paymentMean/%s"
+
+	// mandateReferenceMaxLength is the maximum length of a SEPA mandate reference.
+	mandateReferenceMaxLength = 35
+	// mandateReferenceCharacters are the characters, besides letters and digits, a SEPA mandate reference may contain.
+	mandateReferenceCharacters = "+?/-:().,'"
+)
+
+// Possible payment mean types.
+const (
+	PaymentMeanTypeSEPADirectDebit PaymentMeanType = "SEPA_DIRECT_DEBIT"
+	PaymentMeanTypeBankTransfer    PaymentMeanType = "BANK_TRANSFER"
+)
+
+type (
+	// PaymentMeanType represents a possible type of payment mean.
+	PaymentMeanType string
+
+	// CustomerPaymentMean represents a triPica customer mean of payment.
+	CustomerPaymentMean struct {
+		OUID            string                             `json:"ouid,omitempty"`
+		Type            PaymentMeanType                    `json:"type"`
+		StartDateTime   Date                               `json:"startDateTime"`
+		EndDateTime     Date                               `json:"endDateTime"`
+		Characteristics CustomerPaymentMeanCharacteristics `json:"characteristics"`
+		Extensions      Extensions                         `json:"-"`
+	}
+
+	// CustomerPaymentMeanCharacteristics represents Characteristics part of CustomerPaymentMean.
+	CustomerPaymentMeanCharacteristics struct {
+		IBAN                 string     `json:"iban"`
+		BIC                  string     `json:"bic,omitempty"`
+		AccountHolder        string     `json:"accountHolder,omitempty"`
+		MandateReference     string     `json:"mandateReference,omitempty"`
+		MandateSignatureDate Date       `json:"mandateSignatureDate"`
+		Extensions           Extensions `json:"-"`
+	}
+
+	// SEPADirectDebitRequest represents a request adding a SEPA direct debit payment mean to a customer.
+	// StartDateTime is optional, and defaults to the time triPica receives the request.
+	SEPADirectDebitRequest struct {
+		IBAN                 string
+		BIC                  string
+		AccountHolder        string
+		MandateReference     string
+		MandateSignatureDate time.Time
+		StartDateTime        time.Time
+	}
+)
+
+// IsActiveAt determines whether the payment mean is valid at the provided time.
+// Unset start and end dates leave the period open.
+func (m *CustomerPaymentMean) IsActiveAt(now time.Time) bool {
+	if !m.StartDateTime.IsZero() && now.Before(m.StartDateTime.Time) {
+		return false
+	}
+
+	return m.EndDateTime.IsZero() || now.Before(m.EndDateTime.Time)
+}
+
+// ActivePaymentMean returns the payment mean which is active at the provided time, or nil if there is none.
+// If several payment means are active, the most recently started one is returned.
+func ActivePaymentMean(means []CustomerPaymentMean, now time.Time) *CustomerPaymentMean {
+	var active *CustomerPaymentMean
+
+	for i := range means {
+		m := &means[i]
+		if m.IsActiveAt(now) && (active == nil || m.StartDateTime.After(active.StartDateTime.Time)) {
+			active = m
+		}
+	}
+
+	return active
+}
+
+// ActivePaymentMean returns the customer's payment mean which is active at the provided time, or nil if there is none.
+func (c *Customer) ActivePaymentMean(now time.Time) *CustomerPaymentMean {
+	return ActivePaymentMean(c.PaymentMeans, now)
+}
+
+// ValidateIBAN checks the IBAN, e.g. before forwarding it to banking partners.
+func (c *CustomerPaymentMeanCharacteristics) ValidateIBAN() error {
+	return iban.Validate(c.IBAN)
+}
+
+// MaskedIBAN returns the IBAN masked for display, e.g. "DE** **** **** **** **30 00".
+func (c *CustomerPaymentMeanCharacteristics) MaskedIBAN() string {
+	return iban.Mask(c.IBAN)
+}
+
+// Validate returns a ValidationError listing all invalid fields of the request.
+func (r *SEPADirectDebitRequest) Validate() error {
+	var errs fieldErrors
+
+	errs.require("iban", r.IBAN)
+
+	if r.IBAN != "" {
+		if err := iban.Validate(r.IBAN); err != nil {
+			errs.add("iban", FieldErrorInvalid, err.Error())
+		}
+	}
+
+	if r.BIC != "" {
+		if err := iban.ValidateBIC(r.BIC); err != nil {
+			errs.add("bic", FieldErrorInvalid, err.Error())
+		}
+	}
+
+	errs.require("accountHolder", r.AccountHolder)
+	errs.require("mandateReference", r.MandateReference)
+
+	if r.MandateReference != "" && !isMandateReference(r.MandateReference) {
+		errs.add("mandateReference", FieldErrorInvalid, fmt.Sprintf(
+			"needs to consist of at most %d letters, digits or %s", mandateReferenceMaxLength, mandateReferenceCharacters,
+		))
+	}
+
+	if r.MandateSignatureDate.IsZero() {
+		errs.add("mandateSignatureDate", FieldErrorRequired, "is required")
+	}
+
+	return errs.err()
+}
+
+// paymentMean returns the payment mean the request adds.
+func (r *SEPADirectDebitRequest) paymentMean() *CustomerPaymentMean {
+	m := &CustomerPaymentMean{
+		Type: PaymentMeanTypeSEPADirectDebit,
+		Characteristics: CustomerPaymentMeanCharacteristics{
+			IBAN:                 iban.Normalize(r.IBAN),
+			BIC:                  iban.NormalizeBIC(r.BIC),
+			AccountHolder:        strings.TrimSpace(r.AccountHolder),
+			MandateReference:     r.MandateReference,
+			MandateSignatureDate: NewDate(r.MandateSignatureDate),
+		},
+	}
+
+	if !r.StartDateTime.IsZero() {
+		m.StartDateTime = NewDate(r.StartDateTime)
+	}
+
+	return m
+}
+
+// GetPaymentMeans retrieves all payment means of the customer, including expired ones.
+func (c *customerAPI) GetPaymentMeans(ctx context.Context, customerOUID string) ([]CustomerPaymentMean, error) {
+	resp, err := c.httpClient.Get(
+		fmt.Sprintf(c.address+c.endpoints.path(EndpointCustomerPaymentMeans), customerOUID),
+		http.WithContext(ctx),
+	)
+	if err != nil {
+		return nil, NewTriPicaError(errors.NewHTTPRequestError(err))
+	}
+
+	if resp.StatusCode() == gohttp.StatusNoContent {
+		return []CustomerPaymentMean{}, nil
+	}
+
+	if resp.StatusCode() != gohttp.StatusOK {
+		err := &errors.HTTPError{
+			Body:       string(resp.Body()),
+			StatusCode: resp.StatusCode(),
+		}
+
+		return nil, NewTriPicaError(
+			fmt.Errorf("couldn't retrieve payment means with customerOUID %s: %w", customerOUID, err),
+		)
+	}
+
+	var means []CustomerPaymentMean
+	if err := c.decoder.decode(c.endpoints.path(EndpointCustomerPaymentMeans), resp.Body(), &means); err != nil {
+		return nil, NewTriPicaError(errors.NewParseError(err, resp.Body()))
+	}
+
+	return means, nil
+}
+
+// AddSEPADirectDebit adds a SEPA direct debit payment mean to the customer, returning the created payment mean.
+func (c *customerAPI) AddSEPADirectDebit(
+	ctx context.Context,
+	customerOUID string,
+	req *SEPADirectDebitRequest,
+) (*CustomerPaymentMean, error) {
+	if err := req.Validate(); err != nil {
+		return nil, NewTriPicaError(fmt.Errorf("couldn't add payment mean to customer with ouid %s: %w", customerOUID, err))
+	}
+
+	var mean CustomerPaymentMean
+	if err := (mutation{
+		method:      gohttp.MethodPost,
+		url:         fmt.Sprintf(c.address+c.endpoints.path(EndpointCustomerPaymentMeans), customerOUID),
+		endpoint:    c.endpoints.path(EndpointCustomerPaymentMeans),
+		body:        req.paymentMean(),
+		resource:    "payment mean",
+		description: "add payment mean to customer with ouid " + customerOUID,
+	}).send(ctx, c.httpClient, c.decoder, &mean); err != nil {
+		return nil, err
+	}
+
+	return &mean, nil
+}
+
+// ExpirePaymentMean ends the validity of the customer's payment mean at the provided time,
+// returning the expired payment mean.
+func (c *customerAPI) ExpirePaymentMean(
+	ctx context.Context,
+	customerOUID, paymentMeanOUID string,
+	at time.Time,
+) (*CustomerPaymentMean, error) {
+	var errs fieldErrors
+	if at.IsZero() {
+		errs.add("endDateTime", FieldErrorRequired, "is required")
+	}
+
+	if err := errs.err(); err != nil {
+		return nil, NewTriPicaError(fmt.Errorf("couldn't expire payment mean with ouid %s: %w", paymentMeanOUID, err))
+	}
+
+	var mean CustomerPaymentMean
+	if err := (mutation{
+		method:      gohttp.MethodPatch,
+		url:         fmt.Sprintf(c.address+c.endpoints.path(EndpointCustomerPaymentMean), customerOUID, paymentMeanOUID),
+		endpoint:    c.endpoints.path(EndpointCustomerPaymentMean),
+		body:        validityEnd{EndDateTime: NewDate(at)},
+		resource:    "payment mean",
+		ouid:        paymentMeanOUID,
+		description: "expire payment mean with ouid " + paymentMeanOUID,
+	}).send(ctx, c.httpClient, c.decoder, &mean); err != nil {
+		return nil, err
+	}
+
+	return &mean, nil
+}
+
+// ReplacePaymentMean adds a SEPA direct debit payment mean to the customer, and expires the replaced
+// payment mean once the new one starts. The request's StartDateTime is required, so that there is neither
+// a gap nor an overlap between both payment means. If the replaced payment mean can't be expired,
+// the added payment mean is returned together with the error.
+func (c *customerAPI) ReplacePaymentMean(
+	ctx context.Context,
+	customerOUID, paymentMeanOUID string,
+	req *SEPADirectDebitRequest,
+) (*CustomerPaymentMean, error) {
+	var errs fieldErrors
+	if req.StartDateTime.IsZero() {
+		errs.add("startDateTime", FieldErrorRequired, "is required")
+	}
+
+	if err := errs.err(); err != nil {
+		return nil, NewTriPicaError(fmt.Errorf("couldn't replace payment mean with ouid %s: %w", paymentMeanOUID, err))
+	}
+
+	added, err := c.AddSEPADirectDebit(ctx, customerOUID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := c.ExpirePaymentMean(ctx, customerOUID, paymentMeanOUID, req.StartDateTime); err != nil {
+		return added, fmt.Errorf("added payment mean with ouid %s, but couldn't expire the replaced one: %w", added.OUID, err)
+	}
+
+	return added, nil
+}
+
+// UnmarshalJSON decodes the CustomerPaymentMean, retaining undeclared properties in its Extensions.
+func (m *CustomerPaymentMean) UnmarshalJSON(data []byte) error {
+	type customerPaymentMean CustomerPaymentMean
+
+	return unmarshalExtended(data, (*customerPaymentMean)(m), &m.Extensions)
+}
+
+// MarshalJSON encodes the CustomerPaymentMean, including its Extensions.
+func (m CustomerPaymentMean) MarshalJSON() ([]byte, error) {
+	type customerPaymentMean CustomerPaymentMean
+
+	return marshalExtended(customerPaymentMean(m), m.Extensions)
+}
+
+// UnmarshalJSON decodes the CustomerPaymentMeanCharacteristics, retaining undeclared properties in its Extensions.
+func (c *CustomerPaymentMeanCharacteristics) UnmarshalJSON(data []byte) error {
+	type customerPaymentMeanCharacteristics CustomerPaymentMeanCharacteristics
+
+	return unmarshalExtended(data, (*customerPaymentMeanCharacteristics)(c), &c.Extensions)
+}
+
+// MarshalJSON encodes the CustomerPaymentMeanCharacteristics, including its Extensions.
+func (c CustomerPaymentMeanCharacteristics) MarshalJSON() ([]byte, error) {
+	type customerPaymentMeanCharacteristics CustomerPaymentMeanCharacteristics
+
+	return marshalExtended(customerPaymentMeanCharacteristics(c), c.Extensions)
+}
+
+// isMandateReference determines whether the value is a valid SEPA mandate reference.
+func isMandateReference(value string) bool {
+	if len(value) > mandateReferenceMaxLength {
+		return false
+	}
+
+	for _, r := range value {
+		isAlphanumeric := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+		if !isAlphanumeric && !strings.ContainsRune(mandateReferenceCharacters, r) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,40 @@
+package tripica
+
+import (
+	goerrors "errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSEPADirectDebitRequest_Validate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	const invalidIBAN = "DE89370400440532013001"
+
+	r := SEPADirectDebitRequest{
+		IBAN:                 invalidIBAN,
+		BIC:                  "CHASUS33",
+		AccountHolder:        "Max Muster",
+		MandateReference:     "M-1",
+		MandateSignatureDate: time.Now(),
+	}
+
+	err := r.Validate()
+	require.Error(err)
+	assert.NotContains(err.Error(), invalidIBAN)
+
+	var validationErr *ValidationError
+	require.True(goerrors.As(err, &validationErr))
+
+	fields := map[string]string{}
+	for _, fieldErr := range validationErr.Fields {
+		fields[fieldErr.Field] = fieldErr.Message
+	}
+
+	assert.Equal("IBAN DE** **** **** **** **30 01: invalid checksum", fields["iban"])
+	assert.Contains(fields, "bic")
+}
@@ -0,0 +1,126 @@
+// Package phone normalizes phone numbers to the international E.164 format, e.g. "+4930123456".
+package phone
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	internationalPrefix = "00"
+	trunkPrefix         = "0"
+	// e164MinDigits and e164MaxDigits bound the digits of an E.164 number, including the country calling code.
+	e164MinDigits = 7
+	e164MaxDigits = 15
+)
+
+var (
+	// ErrInvalidCharacters is returned for numbers containing characters other than digits, separators and a
+	// leading plus.
+	ErrInvalidCharacters = errors.New("contains characters other than digits and separators")
+	// ErrMissingCountryCode is returned for national numbers if no default country calling code was provided.
+	ErrMissingCountryCode = errors.New("missing country calling code")
+	// ErrInvalidLength is returned for numbers which have too few or too many digits.
+	ErrInvalidLength = errors.New("invalid length")
+	// ErrInvalidFormat is returned for numbers which can't be interpreted as phone numbers.
+	ErrInvalidFormat = errors.New("invalid format")
+
+	// callingCodes are the country calling codes of the countries triPica customers commonly live in.
+	callingCodes = map[string]string{
+		"AT": "43", "BE": "32", "CH": "41", "CZ": "420", "DE": "49", "DK": "45", "ES": "34", "FI": "358",
+		"FR": "33", "GB": "44", "GR": "30", "HR": "385", "HU": "36", "IE": "353", "IT": "39", "LI": "423",
+		"LU": "352", "NL": "31", "NO": "47", "PL": "48", "PT": "351", "SE": "46", "SI": "386", "SK": "421",
+		"SM": "378", "VA": "379",
+	}
+
+	// trunkPrefixKept are the calling codes of countries whose national numbers keep the leading 0 when dialled
+	// internationally, e.g. "06 1234 5678" in Rome becomes "+39 06 1234 5678".
+	trunkPrefixKept = map[string]bool{"39": true, "378": true, "379": true}
+)
+
+// CountryCallingCode returns the calling code of the ISO 3166 country, e.g. "49" for "DE".
+func CountryCallingCode(country string) (string, bool) {
+	code, ok := callingCodes[strings.ToUpper(strings.TrimSpace(country))]
+
+	return code, ok
+}
+
+// Normalize converts the number to E.164. Spaces, hyphens, dots, slashes and parentheses are ignored, as is
+// a trunk prefix written as "(0)" within an international number, e.g. "+49 (0)30 123456".
+// International numbers start with "+" or "00". National numbers start with the trunk prefix "0", which
+// is replaced by the default country calling code, e.g. "49"; without one, ErrMissingCountryCode is returned.
+// In Italy, San Marino and the Vatican the 0 is part of the number, so it is kept, and national numbers
+// which don't start with it, e.g. mobile numbers, are accepted as well.
+func Normalize(number, defaultCountryCode string) (string, error) {
+	digits := strings.ReplaceAll(strings.TrimSpace(number), "(0)", "")
+	digits = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '-', '.', '/', '(', ')':
+			return -1
+		}
+
+		return r
+	}, digits)
+
+	international := strings.HasPrefix(digits, "+")
+	digits = strings.TrimPrefix(digits, "+")
+
+	if !isDigits(digits) {
+		return "", fmt.Errorf("phone number %s: %w", number, ErrInvalidCharacters)
+	}
+
+	switch {
+	case international:
+	case strings.HasPrefix(digits, internationalPrefix):
+		digits = strings.TrimPrefix(digits, internationalPrefix)
+	case strings.HasPrefix(digits, trunkPrefix), digits != "" && trunkPrefixKept[defaultCountryCode]:
+		if defaultCountryCode == "" {
+			return "", fmt.Errorf("phone number %s: %w", number, ErrMissingCountryCode)
+		}
+
+		if !isDigits(defaultCountryCode) || defaultCountryCode[0] == '0' {
+			return "", fmt.Errorf("country calling code %s: %w", defaultCountryCode, ErrInvalidFormat)
+		}
+
+		if !trunkPrefixKept[defaultCountryCode] {
+			digits = strings.TrimPrefix(digits, trunkPrefix)
+		}
+
+		digits = defaultCountryCode + digits
+	case digits == "":
+		return "", fmt.Errorf("phone number %s: %w", number, ErrInvalidLength)
+	default:
+		return "", fmt.Errorf("phone number %s: %w, expected an international number or a trunk prefix",
+			number, ErrInvalidFormat)
+	}
+
+	if digits != "" && digits[0] == '0' {
+		return "", fmt.Errorf("phone number %s: %w, country calling codes don't start with 0", number, ErrInvalidFormat)
+	}
+
+	if len(digits) < e164MinDigits || len(digits) > e164MaxDigits {
+		return "", fmt.Errorf("phone number %s: %w, expected %d to %d digits",
+			number, ErrInvalidLength, e164MinDigits, e164MaxDigits)
+	}
+
+	return "+" + digits, nil
+}
+
+// IsE164 determines whether the number is already normalized, i.e. a plus followed by 7 to 15 digits.
+func IsE164(number string) bool {
+	digits := strings.TrimPrefix(number, "+")
+
+	return len(digits) < len(number) && len(digits) >= e164MinDigits && len(digits) <= e164MaxDigits &&
+		isDigits(digits) && digits[0] != '0'
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,109 @@
+package phone_test
+
+import (
+	"errors"
+	"testing"
+	"tripica-client/phone"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	assert := assert.New(t)
+
+	for number, expected := range map[string]string{
+		"+49 30 123456":        "+4930123456",
+		"+49 (0)30 / 123-456":  "+4930123456",
+		"0049 30 123456":       "+4930123456",
+		"030 123456":           "+4930123456",
+		"(030) 12.34.56":       "+4930123456",
+		"+43 1 234567":         "+431234567",
+		"  +1 (212) 555-0100 ": "+12125550100",
+	} {
+		normalized, err := phone.Normalize(number, "49")
+		assert.NoError(err, number)
+		assert.Equal(expected, normalized, number)
+	}
+
+	for number, expected := range map[string]error{
+		"030 12345a":            phone.ErrInvalidCharacters,
+		"+49+30123456":          phone.ErrInvalidCharacters,
+		"30123456":              phone.ErrInvalidFormat,
+		"+0301234567":           phone.ErrInvalidFormat,
+		"+49 30":                phone.ErrInvalidLength,
+		"+49 3012 3456 7890 12": phone.ErrInvalidLength,
+		"":                      phone.ErrInvalidLength,
+	} {
+		_, err := phone.Normalize(number, "49")
+		assert.True(errors.Is(err, expected), "%s: %v", number, err)
+	}
+
+	_, err := phone.Normalize("030 123456", "")
+	assert.True(errors.Is(err, phone.ErrMissingCountryCode))
+
+	normalized, err := phone.Normalize("+49 30 123456", "")
+	assert.NoError(err)
+	assert.Equal("+4930123456", normalized)
+}
+
+func TestNormalize_Countries(t *testing.T) {
+	tests := []struct {
+		country string
+		number  string
+		want    string
+		wantErr error
+	}{
+		{country: "DE", number: "030 123456", want: "+4930123456"},
+		{country: "DE", number: "30 123456", wantErr: phone.ErrInvalidFormat},
+		{country: "AT", number: "01 234567", want: "+431234567"},
+		{country: "FR", number: "01 23 45 67 89", want: "+33123456789"},
+		{country: "GB", number: "020 7946 0018", want: "+442079460018"},
+		{country: "NL", number: "020-1234567", want: "+31201234567"},
+		{country: "IT", number: "06 1234 5678", want: "+390612345678"},
+		{country: "IT", number: "347 123 4567", want: "+393471234567"},
+		{country: "IT", number: "+39 06 1234 5678", want: "+390612345678"},
+		{country: "IT", number: "0039 06 1234 5678", want: "+390612345678"},
+		{country: "IT", number: "", wantErr: phone.ErrInvalidLength},
+		{country: "SM", number: "0549 123456", want: "+3780549123456"},
+		{country: "VA", number: "06 6982", want: "+379066982"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.country+" "+tt.number, func(t *testing.T) {
+			code, ok := phone.CountryCallingCode(tt.country)
+			assert.True(t, ok)
+
+			normalized, err := phone.Normalize(tt.number, code)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, normalized)
+		})
+	}
+}
+
+func TestIsE164(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(phone.IsE164("+4930123456"))
+	assert.False(phone.IsE164("4930123456"))
+	assert.False(phone.IsE164("+49 30 123456"))
+	assert.False(phone.IsE164("+0301234567"))
+	assert.False(phone.IsE164("+"))
+}
+
+func TestCountryCallingCode(t *testing.T) {
+	assert := assert.New(t)
+
+	code, ok := phone.CountryCallingCode("de")
+	assert.True(ok)
+	assert.Equal("49", code)
+
+	_, ok = phone.CountryCallingCode("US")
+	assert.False(ok)
+}
@@ -1,18 +1,18 @@
 package tripica
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	gohttp "net/http"
-	"net/url"
 	"strings"
+	"time"
 	"tripica-client/http"
 	"tripica-client/http/errors"
 	"tripica-client/log"
 )
 
 const (
-	productBasePath = "/api/private/v1/agent/product"
+	productBasePath = "/api/private/{version}/agent/product"
 
 	productPathGetByCustomerOuid              = "/customerOuid/%s"
 	productPathGetProductOrdersByCustomerOuid = "/productOrder/customerOuid/%s"
@@ -22,24 +22,28 @@ const (
 type productAPI struct {
 	httpClient *http.Client
 	address    string
+	endpoints  *endpointRegistry
+	decoder    *decoder
 
 	logger log.Logger
 }
 
 // GetProductsByCustomerOUID retrieves products by UOID <=> unique internal identifier.
 func (p *productAPI) GetProductsByCustomerOUID(customerOUID string, filter *ProductDateFilter) ([]Product, error) {
-	url := fmt.Sprintf(p.address+productPathGetByCustomerOuid, customerOUID)
-
-	if filter != nil {
-		f, err := filter.Encode()
-		if err != nil {
-			return nil, err
-		}
+	return p.getProductsByCustomerOUID(context.Background(), customerOUID, filter)
+}
 
-		url += "?filters=" + f
+func (p *productAPI) getProductsByCustomerOUID(
+	ctx context.Context,
+	customerOUID string,
+	filter *ProductDateFilter,
+) ([]Product, error) {
+	url, err := p.filteredURL(EndpointProductsByCustomer, customerOUID, filter.Filter())
+	if err != nil {
+		return nil, err
 	}
 
-	resp, err := p.httpClient.Get(url)
+	resp, err := p.httpClient.Get(url, http.WithContext(ctx))
 	if err != nil {
 		return nil, NewTriPicaError(errors.NewHTTPRequestError(err))
 	}
@@ -58,7 +62,7 @@ func (p *productAPI) GetProductsByCustomerOUID(customerOUID string, filter *Prod
 	}
 
 	var products []Product
-	if err := json.Unmarshal(resp.Body(), &products); err != nil {
+	if err := p.decoder.decode(p.endpoints.path(EndpointProductsByCustomer), resp.Body(), &products); err != nil {
 		return nil, NewTriPicaError(errors.NewParseError(err, resp.Body()))
 	}
 
@@ -70,15 +74,9 @@ func (p *productAPI) GetProductOrdersByCustomerOUID(
 	customerOUID string,
 	filter *ProductDateFilter,
 ) ([]ProductOrder, error) {
-	url := fmt.Sprintf(p.address+productPathGetProductOrdersByCustomerOuid, customerOUID)
-
-	if filter != nil {
-		f, err := filter.Encode()
-		if err != nil {
-			return nil, err
-		}
-
-		url += "?filters=" + f
+	url, err := p.filteredURL(EndpointProductOrdersByCustomer, customerOUID, filter.Filter())
+	if err != nil {
+		return nil, err
 	}
 
 	resp, err := p.httpClient.Get(url)
@@ -104,16 +102,104 @@ func (p *productAPI) GetProductOrdersByCustomerOUID(
 	}
 
 	var productOrders []ProductOrder
-	if err := json.Unmarshal(resp.Body(), &productOrders); err != nil {
+	if err := p.decoder.decode(
+		p.endpoints.path(EndpointProductOrdersByCustomer), resp.Body(), &productOrders,
+	); err != nil {
 		return nil, NewTriPicaError(errors.NewParseError(err, resp.Body()))
 	}
 
 	return productOrders, nil
 }
 
+// IterateProductsByCustomerOUID lazily iterates over the customer's products, fetching pageSize products at once.
+func (p *productAPI) IterateProductsByCustomerOUID(
+	ctx context.Context,
+	customerOUID string,
+	filter *Filter,
+	pageSize int,
+) *Iterator[Product] {
+	url, err := p.filteredURL(EndpointProductsByCustomer, customerOUID, filter)
+
+	return newIterator(ctx, pageSize, func(ctx context.Context, offset, limit int) ([]Product, error) {
+		if err != nil {
+			return nil, err
+		}
+
+		return getPage[Product](
+			ctx, p.httpClient, p.decoder, url, p.endpoints.path(EndpointProductsByCustomer), offset, limit,
+			"products with customerOUID "+customerOUID,
+		)
+	})
+}
+
+// IterateProductOrdersByCustomerOUID lazily iterates over the customer's product orders,
+// fetching pageSize product orders at once.
+func (p *productAPI) IterateProductOrdersByCustomerOUID(
+	ctx context.Context,
+	customerOUID string,
+	filter *Filter,
+	pageSize int,
+) *Iterator[ProductOrder] {
+	url, err := p.filteredURL(EndpointProductOrdersByCustomer, customerOUID, filter)
+
+	return newIterator(ctx, pageSize, func(ctx context.Context, offset, limit int) ([]ProductOrder, error) {
+		if err != nil {
+			return nil, err
+		}
+
+		return getPage[ProductOrder](
+			ctx, p.httpClient, p.decoder, url, p.endpoints.path(EndpointProductOrdersByCustomer), offset, limit,
+			"product orders with customerOUID "+customerOUID,
+		)
+	})
+}
+
+// StreamProductsByCustomerOUID streams the customer's products matching the optional filter,
+// calling fn for each product as soon as it is decoded.
+func (p *productAPI) StreamProductsByCustomerOUID(
+	ctx context.Context,
+	customerOUID string,
+	filter *Filter,
+	fn func(Product) error,
+) error {
+	url, err := p.filteredURL(EndpointProductsByCustomer, customerOUID, filter)
+	if err != nil {
+		return err
+	}
+
+	return streamArray(
+		ctx, p.httpClient, p.decoder, url, p.endpoints.path(EndpointProductsByCustomer),
+		"products with customerOUID "+customerOUID, fn,
+	)
+}
+
+// StreamProductOrdersByCustomerOUID streams the customer's product orders matching the optional filter,
+// calling fn for each product order as soon as it is decoded.
+func (p *productAPI) StreamProductOrdersByCustomerOUID(
+	ctx context.Context,
+	customerOUID string,
+	filter *Filter,
+	fn func(ProductOrder) error,
+) error {
+	url, err := p.filteredURL(EndpointProductOrdersByCustomer, customerOUID, filter)
+	if err != nil {
+		return err
+	}
+
+	return streamArray(
+		ctx, p.httpClient, p.decoder, url, p.endpoints.path(EndpointProductOrdersByCustomer),
+		"product orders with customerOUID "+customerOUID, fn,
+	)
+}
+
+// filteredURL builds the URL of a customer's product endpoint, with the optional filter applied.
+func (p *productAPI) filteredURL(endpoint Endpoint, customerOUID string, filter *Filter) (string, error) {
+	return withFilter(fmt.Sprintf(p.address+p.endpoints.path(endpoint), customerOUID), filter)
+}
+
 // Product represents a triPica product.
 type Product struct {
-	OUID                     string                 `json:"ouid"`
+	OUID                     string                 `json:"ouid" tripica:"required"`
 	Version                  int                    `json:"version"`
 	DateTimeCreate           Date                   `json:"dateTimeCreate"`
 	CreatorOUID              string                 `json:"creatorOuid"`
@@ -121,16 +207,17 @@ type Product struct {
 	ModifierOUID             string                 `json:"modifierOuid"`
 	StartDateTime            Date                   `json:"startDateTime"`
 	EndDateTime              Date                   `json:"endDateTime"`
-	Name                     string                 `json:"name"`
+	Name                     string                 `json:"name" tripica:"required"`
 	OrderDate                Date                   `json:"orderDate"`
 	NextRenewalDate          Date                   `json:"nextRenewalDate"`
-	BillingAccountOuid       string                 `json:"billingAccountOuid"`
+	BillingAccountOuid       string                 `json:"billingAccountOuid" tripica:"required"`
 	ProductOfferingOuid      string                 `json:"productOfferingOuid"`
 	ProductSpecificationOuid string                 `json:"productSpecificationOuid"`
 	ProductSerialNumber      string                 `json:"productSerialNumber,omitempty"`
 	RealizingService         string                 `json:"realizingService"`
-	Status                   string                 `json:"status"`
+	Status                   string                 `json:"status" tripica:"required"`
 	Characteristics          ProductCharacteristics `json:"characteristics"`
+	Extensions               Extensions             `json:"-"`
 }
 
 const (
@@ -146,7 +233,8 @@ func (p Product) IsSubscriptionProduct() bool {
 
 // ProductCharacteristics represents the Characteristics part of a product.
 type ProductCharacteristics struct {
-	MeterNumber string `json:"meterData.meterNumber"`
+	MeterNumber string     `json:"meterData.meterNumber"`
+	Extensions  Extensions `json:"-"`
 }
 
 // IsActive returns true if product is in active status.
@@ -154,6 +242,20 @@ func (p *Product) IsActive() bool {
 	return p.Status == ActiveProductStatus
 }
 
+// IsActiveAt returns true if product is in active status, and the provided time is within its validity period.
+// Unset start and end dates leave the period open.
+func (p *Product) IsActiveAt(now time.Time) bool {
+	if !p.IsActive() {
+		return false
+	}
+
+	if !p.StartDateTime.IsZero() && now.Before(p.StartDateTime.Time) {
+		return false
+	}
+
+	return p.EndDateTime.IsZero() || now.Before(p.EndDateTime.Time)
+}
+
 // HasContract returns true if product has a contract.
 func (p *Product) HasContract() bool {
 	return p.ProductSerialNumber != ""
@@ -161,17 +263,19 @@ func (p *Product) HasContract() bool {
 
 // ProductOrder represents a triPica product order.
 type ProductOrder struct {
-	OUID        string             `json:"ouid"`
+	OUID        string             `json:"ouid" tripica:"required"`
 	Category    string             `json:"category"`
 	Description string             `json:"description"`
 	OrderDate   Date               `json:"orderDate"`
 	OrderItems  []ProductOrderItem `json:"orderItems"`
+	Extensions  Extensions         `json:"-"`
 }
 
 // ProductOrderItem represents a triPica product order item.
 type ProductOrderItem struct {
-	Product            Product `json:"product"`
-	BillingAccountOUID string  `json:"billingAccountOuid"`
+	Product            Product    `json:"product"`
+	BillingAccountOUID string     `json:"billingAccountOuid"`
+	Extensions         Extensions `json:"-"`
 }
 
 // ProductDateFilter is used to filter products by their endDateTime and startDateTime values.
@@ -180,14 +284,35 @@ type ProductDateFilter struct {
 	End   *int `json:"end,omitempty"`   // Minimum product startDateTime in milliseconds.
 }
 
+// Filter converts the date filter into a Filter, so that it can be combined with other conditions.
+// Unlike Filter.DateRange, Begin and End bound different dates of the products, so they are passed on
+// as they are, even if Begin lies after End.
+func (f *ProductDateFilter) Filter() *Filter {
+	if f == nil {
+		return nil
+	}
+
+	filter := NewFilter()
+
+	if f.Begin != nil {
+		filter.conditions[filterFieldBegin] = *f.Begin
+	}
+
+	if f.End != nil {
+		filter.conditions[filterFieldEnd] = *f.End
+	}
+
+	return filter
+}
+
 // Encode encodes the filter, allowing it to be used when making HTTP requests to triPica.
+// A nil filter is encoded as null.
 func (f *ProductDateFilter) Encode() (string, error) {
-	v, err := json.Marshal(f)
-	if err != nil {
-		return "", err
+	if f == nil {
+		return "null", nil
 	}
 
-	return url.QueryEscape(string(v)), nil
+	return f.Filter().Encode()
 }
 
 // ProductsAssociatedToBillingAccounts  filters products based on their association with provided billing accounts.
@@ -218,3 +343,59 @@ func SubscriptionProducts(products []Product) []Product {
 
 	return subscriptionProducts
 }
+
+// UnmarshalJSON decodes the Product, retaining undeclared properties in its Extensions.
+func (p *Product) UnmarshalJSON(data []byte) error {
+	type product Product
+
+	return unmarshalExtended(data, (*product)(p), &p.Extensions)
+}
+
+// MarshalJSON encodes the Product, including its Extensions.
+func (p Product) MarshalJSON() ([]byte, error) {
+	type product Product
+
+	return marshalExtended(product(p), p.Extensions)
+}
+
+// UnmarshalJSON decodes the ProductCharacteristics, retaining undeclared properties in its Extensions.
+func (c *ProductCharacteristics) UnmarshalJSON(data []byte) error {
+	type productCharacteristics ProductCharacteristics
+
+	return unmarshalExtended(data, (*productCharacteristics)(c), &c.Extensions)
+}
+
+// MarshalJSON encodes the ProductCharacteristics, including its Extensions.
+func (c ProductCharacteristics) MarshalJSON() ([]byte, error) {
+	type productCharacteristics ProductCharacteristics
+
+	return marshalExtended(productCharacteristics(c), c.Extensions)
+}
+
+// UnmarshalJSON decodes the ProductOrder, retaining undeclared properties in its Extensions.
+func (o *ProductOrder) UnmarshalJSON(data []byte) error {
+	type productOrder ProductOrder
+
+	return unmarshalExtended(data, (*productOrder)(o), &o.Extensions)
+}
+
+// MarshalJSON encodes the ProductOrder, including its Extensions.
+func (o ProductOrder) MarshalJSON() ([]byte, error) {
+	type productOrder ProductOrder
+
+	return marshalExtended(productOrder(o), o.Extensions)
+}
+
+// UnmarshalJSON decodes the ProductOrderItem, retaining undeclared properties in its Extensions.
+func (i *ProductOrderItem) UnmarshalJSON(data []byte) error {
+	type productOrderItem ProductOrderItem
+
+	return unmarshalExtended(data, (*productOrderItem)(i), &i.Extensions)
+}
+
+// MarshalJSON encodes the ProductOrderItem, including its Extensions.
+func (i ProductOrderItem) MarshalJSON() ([]byte, error) {
+	type productOrderItem ProductOrderItem
+
+	return marshalExtended(productOrderItem(i), i.Extensions)
+}
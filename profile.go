@@ -0,0 +1,284 @@
+package tripica
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultProfileConcurrency is the number of sections loaded at once, unless specified otherwise.
+const defaultProfileConcurrency = 4
+
+// Possible sections of a CustomerProfile, besides the customer itself, which is always loaded.
+const (
+	SectionIndividual      ProfileSection = "individual"
+	SectionBillingAccounts ProfileSection = "billingAccounts"
+	SectionProducts        ProfileSection = "products"
+	SectionDueBalances     ProfileSection = "dueBalances"
+	SectionLogin           ProfileSection = "login"
+)
+
+var (
+	errNoPartyRef     = errors.New("customer has no party reference")
+	errUnknownSection = errors.New("unknown customer profile section")
+)
+
+type (
+	// ProfileSection represents a part of a CustomerProfile which is loaded from a separate endpoint.
+	ProfileSection string
+
+	// CustomerProfile aggregates everything known about a customer. Sections which weren't requested,
+	// or couldn't be loaded, are left empty, and the errors of the latter are reported in Errors.
+	CustomerProfile struct {
+		Customer        *Customer
+		Individual      *Individual
+		BillingAccounts []*BillingAccount
+		Products        []Product
+		DueBalances     []*BillingAccountBalance
+		Login           *Login
+		Errors          map[ProfileSection]error
+	}
+
+	// PartialProfileError is returned by CustomerProfile.Err if some sections couldn't be loaded.
+	PartialProfileError struct {
+		Errors map[ProfileSection]error
+	}
+
+	// ProfileOption represents a functional option used to configure the loading of a CustomerProfile.
+	ProfileOption func(*profileConfig)
+
+	profileConfig struct {
+		sections      map[ProfileSection]bool
+		concurrency   int
+		productFilter *ProductDateFilter
+	}
+)
+
+// AllProfileSections returns every section of a CustomerProfile.
+func AllProfileSections() []ProfileSection {
+	return []ProfileSection{SectionIndividual, SectionBillingAccounts, SectionProducts, SectionDueBalances, SectionLogin}
+}
+
+// WithSections restricts the loaded sections to the provided ones. All sections are loaded by default.
+func WithSections(sections ...ProfileSection) ProfileOption {
+	return func(c *profileConfig) {
+		c.sections = make(map[ProfileSection]bool, len(sections))
+		for _, s := range sections {
+			c.sections[s] = true
+		}
+	}
+}
+
+// WithConcurrency limits the number of sections loaded at once. Non-positive values fall back to the default of 4.
+func WithConcurrency(n int) ProfileOption {
+	return func(c *profileConfig) {
+		if n > 0 {
+			c.concurrency = n
+		}
+	}
+}
+
+// WithProductFilter filters the products of the profile.
+func WithProductFilter(filter *ProductDateFilter) ProfileOption {
+	return func(c *profileConfig) {
+		c.productFilter = filter
+	}
+}
+
+// LoadCustomerProfile retrieves the customer, followed by the requested sections of its profile, which are loaded
+// concurrently. All requests are bound to the context. An error is only returned if a requested section is unknown,
+// or the customer itself couldn't be retrieved, while the errors of sections are reported by the profile.
+// If the customer doesn't exist, neither a profile nor an error is returned.
+//
+//	profile, err := client.LoadCustomerProfile(ctx, ouid, tripica.WithSections(tripica.SectionIndividual))
+//	if err != nil || profile == nil {
+//		...
+//	}
+//	if err := profile.Err(); err != nil {
+//		// render the sections which could be loaded
+//	}
+func (c *Client) LoadCustomerProfile(
+	ctx context.Context,
+	customerOUID string,
+	options ...ProfileOption,
+) (*CustomerProfile, error) {
+	config := &profileConfig{concurrency: defaultProfileConcurrency}
+	WithSections(AllProfileSections()...)(config)
+
+	for _, option := range options {
+		option(config)
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	customer, err := c.getCustomerByOUID(ctx, customerOUID)
+	if err != nil || customer == nil {
+		return nil, err
+	}
+
+	profile := &CustomerProfile{
+		Customer: customer,
+		Errors:   map[ProfileSection]error{},
+	}
+
+	loaders := map[ProfileSection]func() error{
+		SectionIndividual: func() (err error) {
+			if customer.PartyRef.PartyOUID == "" {
+				return errNoPartyRef
+			}
+
+			profile.Individual, err = c.getIndividualByPartyOUID(ctx, customer.PartyRef.PartyOUID)
+
+			return err
+		},
+		SectionBillingAccounts: func() (err error) {
+			profile.BillingAccounts, err = c.getCustomerBillingAccounts(ctx, customerOUID)
+
+			return err
+		},
+		SectionProducts: func() (err error) {
+			profile.Products, err = c.getProductsByCustomerOUID(ctx, customerOUID, config.productFilter)
+
+			return err
+		},
+		SectionDueBalances: func() (err error) {
+			profile.DueBalances, err = c.getDueBillingAccountBalancesByCustomer(ctx, customerOUID)
+
+			return err
+		},
+		SectionLogin: func() (err error) {
+			profile.Login, err = c.getLoginByCustomerOUID(ctx, customerOUID)
+
+			return err
+		},
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mux       sync.Mutex
+		semaphore = make(chan struct{}, config.concurrency)
+	)
+
+	for _, section := range AllProfileSections() {
+		if !config.sections[section] {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(section ProfileSection, load func() error) {
+			defer wg.Done()
+
+			var err error
+
+			select {
+			case semaphore <- struct{}{}:
+				err = load()
+				<-semaphore
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+
+			if err == nil {
+				return
+			}
+
+			c.logger.WithFields(map[string]interface{}{
+				"customer_ouid": customerOUID,
+				"section":       string(section),
+				"error":         err.Error(),
+			}).Warn("couldn't load customer profile section")
+
+			mux.Lock()
+			profile.Errors[section] = err
+			mux.Unlock()
+		}(section, loaders[section])
+	}
+
+	wg.Wait()
+
+	return profile, nil
+}
+
+// validate checks that all requested sections exist.
+func (c *profileConfig) validate() error {
+	known := make(map[ProfileSection]bool, len(c.sections))
+	for _, section := range AllProfileSections() {
+		known[section] = true
+	}
+
+	for _, section := range sortedKeys(c.sections) {
+		if !known[section] {
+			return NewTriPicaError(fmt.Errorf("%w: %s", errUnknownSection, section))
+		}
+	}
+
+	return nil
+}
+
+// Err returns a PartialProfileError if some sections couldn't be loaded, and nil otherwise.
+func (p *CustomerProfile) Err() error {
+	if len(p.Errors) == 0 {
+		return nil
+	}
+
+	return &PartialProfileError{Errors: p.Errors}
+}
+
+// Failed determines whether the section couldn't be loaded.
+func (p *CustomerProfile) Failed(section ProfileSection) bool {
+	return p.Errors[section] != nil
+}
+
+// Error makes PartialProfileError implement the error interface.
+func (e *PartialProfileError) Error() string {
+	sections := make([]string, 0, len(e.Errors))
+	for section, err := range e.Errors {
+		sections = append(sections, fmt.Sprintf("%s: %s", section, err))
+	}
+
+	sort.Strings(sections)
+
+	return fmt.Sprintf("couldn't load customer profile sections: %s", strings.Join(sections, "; "))
+}
+
+// Is determines whether the error of any section matches the target, see errors.Is.
+func (e *PartialProfileError) Is(target error) bool {
+	for _, section := range e.sections() {
+		if errors.Is(e.Errors[section], target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first error of the sections, in the order of their names, which matches the target, see errors.As.
+func (e *PartialProfileError) As(target interface{}) bool {
+	for _, section := range e.sections() {
+		if errors.As(e.Errors[section], target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sections returns the sections which failed, ordered by their names.
+func (e *PartialProfileError) sections() []ProfileSection {
+	sections := make([]ProfileSection, 0, len(e.Errors))
+	for section := range e.Errors {
+		sections = append(sections, section)
+	}
+
+	sort.Slice(sections, func(a, b int) bool {
+		return sections[a] < sections[b]
+	})
+
+	return sections
+}
@@ -0,0 +1,196 @@
+package tripica
+
+import (
+	"context"
+	goerrors "errors"
+	gohttp "net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"tripica-client/http/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	profileCustomerPath = "/api/private/v1/agent/customer/C1"
+	profileBillingPath  = "/api/private/v1/agent/billing/billingAccount/customerOuid/C1"
+)
+
+// profileHandler answers the requests of all profile sections of the customer C1.
+func profileHandler(section gohttp.HandlerFunc) gohttp.HandlerFunc {
+	return func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		switch {
+		case r.URL.Path == profileCustomerPath:
+			_, _ = w.Write([]byte(`{"ouid":"C1","partyRef":{"partyOuid":"P1"}}`))
+		case section != nil:
+			section(w, r)
+		case strings.HasSuffix(r.URL.Path, "/individual/P1"):
+			_, _ = w.Write([]byte(`{"ouid":"P1","givenName":"Max"}`))
+		case strings.HasSuffix(r.URL.Path, "/login/customerOuid/C1"):
+			_, _ = w.Write([]byte(`[{"email":"max@example.com"}]`))
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}
+}
+
+func TestLoadCustomerProfile(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("all sections are loaded", func(t *testing.T) {
+		client := newTestClient(t, profileHandler(nil), nil)
+
+		profile, err := client.LoadCustomerProfile(context.Background(), "C1")
+
+		require.NoError(t, err)
+		assert.NoError(profile.Err())
+		assert.Equal("C1", profile.Customer.OUID)
+		assert.Equal("Max", profile.Individual.Name)
+		assert.Equal("max@example.com", profile.Login.Email)
+		assert.NotNil(profile.BillingAccounts)
+	})
+
+	t.Run("concurrency is limited", func(t *testing.T) {
+		var inFlight, maxInFlight int32
+
+		client := newTestClient(t, profileHandler(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+
+			for {
+				max := atomic.LoadInt32(&maxInFlight)
+				if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+					break
+				}
+			}
+
+			time.Sleep(20 * time.Millisecond)
+			profileHandler(nil)(w, r)
+		}), nil)
+
+		profile, err := client.LoadCustomerProfile(context.Background(), "C1", WithConcurrency(2))
+
+		require.NoError(t, err)
+		assert.NoError(profile.Err())
+		assert.Equal(int32(2), atomic.LoadInt32(&maxInFlight))
+	})
+
+	t.Run("only requested sections are loaded", func(t *testing.T) {
+		var requested sync.Map
+
+		client := newTestClient(t, profileHandler(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+			requested.Store(r.URL.Path, true)
+			_, _ = w.Write([]byte(`[]`))
+		}), nil)
+
+		profile, err := client.LoadCustomerProfile(context.Background(), "C1", WithSections(SectionBillingAccounts))
+
+		require.NoError(t, err)
+		assert.NoError(profile.Err())
+		assert.Nil(profile.Individual)
+
+		_, ok := requested.Load(profileBillingPath)
+		assert.True(ok)
+
+		count := 0
+		requested.Range(func(_, _ interface{}) bool {
+			count++
+
+			return true
+		})
+		assert.Equal(1, count)
+	})
+
+	t.Run("errors of sections are reported by the profile", func(t *testing.T) {
+		client := newTestClient(t, profileHandler(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+			if r.URL.Path == profileBillingPath {
+				w.WriteHeader(gohttp.StatusInternalServerError)
+
+				return
+			}
+
+			profileHandler(nil)(w, r)
+		}), nil)
+
+		profile, err := client.LoadCustomerProfile(context.Background(), "C1")
+
+		require.NoError(t, err)
+		assert.True(profile.Failed(SectionBillingAccounts))
+		assert.False(profile.Failed(SectionIndividual))
+		assert.Equal("Max", profile.Individual.Name)
+
+		var partialErr *PartialProfileError
+		require.True(t, goerrors.As(profile.Err(), &partialErr))
+		assert.Len(partialErr.Errors, 1)
+
+		var httpErr *errors.HTTPError
+		require.True(t, goerrors.As(profile.Err(), &httpErr))
+		assert.Equal(gohttp.StatusInternalServerError, httpErr.StatusCode)
+	})
+
+	t.Run("sections are cancelled with the context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var once sync.Once
+
+		client := newTestClient(t, profileHandler(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+			once.Do(cancel)
+			<-r.Context().Done()
+		}), nil)
+
+		profile, err := client.LoadCustomerProfile(ctx, "C1")
+
+		require.NoError(t, err)
+		assert.Equal("C1", profile.Customer.OUID)
+
+		for _, section := range AllProfileSections() {
+			assert.True(goerrors.Is(profile.Errors[section], context.Canceled), section)
+		}
+
+		assert.True(goerrors.Is(profile.Err(), context.Canceled))
+	})
+
+	t.Run("the customer is retrieved with the context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		client := newTestClient(t, profileHandler(nil), nil)
+
+		profile, err := client.LoadCustomerProfile(ctx, "C1")
+
+		assert.True(goerrors.Is(err, context.Canceled))
+		assert.Nil(profile)
+	})
+
+	t.Run("unknown sections are rejected", func(t *testing.T) {
+		var requests int32
+
+		client := newTestClient(t, gohttp.HandlerFunc(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+			atomic.AddInt32(&requests, 1)
+		}), nil)
+
+		profile, err := client.LoadCustomerProfile(context.Background(), "C1",
+			WithSections(SectionIndividual, "contracts"))
+
+		assert.True(goerrors.Is(err, errUnknownSection))
+		assert.Contains(err.Error(), "contracts")
+		assert.Nil(profile)
+		assert.Zero(atomic.LoadInt32(&requests))
+	})
+
+	t.Run("missing customers aren't reported as errors", func(t *testing.T) {
+		client := newTestClient(t, gohttp.HandlerFunc(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+			w.WriteHeader(gohttp.StatusNoContent)
+		}), nil)
+
+		profile, err := client.LoadCustomerProfile(context.Background(), "C1")
+
+		assert.NoError(err)
+		assert.Nil(profile)
+	})
+}
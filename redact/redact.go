@@ -0,0 +1,276 @@
+// Package redact removes personally identifiable information from triPica payloads
+// before they end up in logs or error messages.
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Mask is the value which replaces redacted data.
+const Mask = "***"
+
+var (
+	defaultRedactor = New(DefaultRules()...)
+	defaultMux      sync.RWMutex
+
+	// keyValuePattern matches the key/value pairs of JSON objects within bodies which can't be parsed, e.g.
+	// truncated ones. String values may lack their closing quote, and other values end at the next delimiter.
+	keyValuePattern = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"\s*:\s*("(?:[^"\\]|\\.)*"?|[^\s,{}\[\]"]+)`)
+)
+
+type (
+	// Rule describes a field which needs to be redacted.
+	// Path is a dot separated list of JSON object keys, matched case-insensitively against
+	// the end of the field's path, so that "iban" matches the field at any depth, while
+	// "medium.street1" only matches street1 fields of a medium object. Array indices are not part of the path,
+	// and "*" matches any single key.
+	// If KeepLast is positive, the last KeepLast characters of string values are kept visible.
+	Rule struct {
+		Path     string
+		KeepLast int
+	}
+
+	// Redactor redacts JSON bodies according to its rules.
+	Redactor struct {
+		rules []rule
+	}
+
+	rule struct {
+		segments []string
+		keepLast int
+	}
+)
+
+// DefaultRules returns the rules covering the PII which triPica commonly returns.
+func DefaultRules() []Rule {
+	return []Rule{
+		{Path: "iban", KeepLast: 4},
+		{Path: "familyName"},
+		{Path: "street1"},
+		{Path: "street2"},
+		{Path: "email"},
+		{Path: "emailAddress"},
+		{Path: "medium.number", KeepLast: 2},
+		{Path: "password"},
+		{Path: "token"},
+	}
+}
+
+// New returns a Redactor applying the provided rules.
+func New(rules ...Rule) *Redactor {
+	r := &Redactor{}
+
+	for _, rl := range rules {
+		if rl.Path == "" {
+			continue
+		}
+
+		r.rules = append(r.rules, rule{
+			segments: strings.Split(strings.ToLower(rl.Path), "."),
+			keepLast: rl.KeepLast,
+		})
+	}
+
+	return r
+}
+
+// Default returns the Redactor used by the library whenever no other one is configured.
+func Default() *Redactor {
+	defaultMux.RLock()
+	defer defaultMux.RUnlock()
+
+	return defaultRedactor
+}
+
+// SetDefault replaces the default Redactor, allowing the rules to be configured per deployment.
+func SetDefault(r *Redactor) {
+	if r == nil {
+		r = New()
+	}
+
+	defaultMux.Lock()
+	defaultRedactor = r
+	defaultMux.Unlock()
+}
+
+// Redact returns a copy of the JSON body with all matching fields masked.
+// In bodies which are not valid JSON, e.g. truncated ones, the values of key/value pairs whose key matches
+// the last segment of a rule are masked, as their paths are unknown.
+func (r *Redactor) Redact(body []byte) []byte {
+	if len(r.rules) == 0 || len(body) == 0 {
+		return body
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return r.redactInvalid(body)
+	}
+
+	v, masked := r.redact(v, nil)
+	if !masked {
+		return body
+	}
+
+	redacted, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+
+	return redacted
+}
+
+// RedactString works like Redact, but with strings.
+func (r *Redactor) RedactString(body string) string {
+	return string(r.Redact([]byte(body)))
+}
+
+// RedactURL masks the values of query parameters matching any of the rules.
+func (r *Redactor) RedactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.RawQuery == "" {
+		return rawURL
+	}
+
+	query := u.Query()
+	masked := false
+
+	for key, values := range query {
+		rl, ok := r.match([]string{strings.ToLower(key)})
+		if !ok {
+			continue
+		}
+
+		for i, value := range values {
+			values[i] = rl.mask(value)
+		}
+
+		masked = true
+	}
+
+	if !masked {
+		return rawURL
+	}
+
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+// redactInvalid masks the values of key/value pairs found in a body which couldn't be decoded.
+func (r *Redactor) redactInvalid(body []byte) []byte {
+	return keyValuePattern.ReplaceAllFunc(body, func(pair []byte) []byte {
+		groups := keyValuePattern.FindSubmatch(pair)
+		key, value := strings.ToLower(string(groups[1])), string(groups[2])
+
+		rl, ok := r.matchKey(key)
+		if !ok {
+			return pair
+		}
+
+		masked := Mask
+		if strings.HasPrefix(value, `"`) {
+			masked = `"` + rl.mask(strings.TrimSuffix(strings.TrimPrefix(value, `"`), `"`)) + `"`
+		}
+
+		return append(pair[:len(pair)-len(value):len(pair)-len(value)], masked...)
+	})
+}
+
+// redact walks through the decoded JSON value, masking the fields matching the rules.
+func (r *Redactor) redact(v interface{}, path []string) (interface{}, bool) {
+	masked := false
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for key, value := range t {
+			fieldPath := append(path[:len(path):len(path)], strings.ToLower(key))
+
+			if rl, ok := r.match(fieldPath); ok {
+				t[key] = rl.maskValue(value)
+				masked = true
+
+				continue
+			}
+
+			var m bool
+			t[key], m = r.redact(value, fieldPath)
+			masked = masked || m
+		}
+	case []interface{}:
+		for i, value := range t {
+			var m bool
+			t[i], m = r.redact(value, path)
+			masked = masked || m
+		}
+	}
+
+	return v, masked
+}
+
+func (r *Redactor) match(path []string) (rule, bool) {
+	for _, rl := range r.rules {
+		if rl.matches(path) {
+			return rl, true
+		}
+	}
+
+	return rule{}, false
+}
+
+// matchKey finds the first rule whose last segment is the key.
+func (r *Redactor) matchKey(key string) (rule, bool) {
+	for _, rl := range r.rules {
+		if rl.segments[len(rl.segments)-1] == key {
+			return rl, true
+		}
+	}
+
+	return rule{}, false
+}
+
+func (rl rule) matches(path []string) bool {
+	if len(rl.segments) > len(path) {
+		return false
+	}
+
+	offset := len(path) - len(rl.segments)
+
+	for i, segment := range rl.segments {
+		if segment != "*" && segment != path[offset+i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (rl rule) maskValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return rl.mask(t)
+	default:
+		return Mask
+	}
+}
+
+func (rl rule) mask(s string) string {
+	if s == "" {
+		return s
+	}
+
+	if rl.keepLast <= 0 || len(s) <= rl.keepLast {
+		return Mask
+	}
+
+	return Mask + s[len(s)-rl.keepLast:]
+}
@@ -0,0 +1,72 @@
+package redact_test
+
+import (
+	"testing"
+	"tripica-client/redact"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactor_Redact(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("default rules mask fields at any depth", func(t *testing.T) {
+		body := `{"ouid":"1","familyName":"Muster","paymentMeans":[{"characteristics":{"iban":"DE89370400440532013000"}}],` +
+			`"contactMediums":[{"medium":{"street1":"Hauptstr. 1","city":"Berlin"}},` +
+			`{"medium":{"type":"PHONE","number":"+4930123456"}}]}`
+
+		redacted := redact.New(redact.DefaultRules()...).RedactString(body)
+
+		assert.JSONEq(`{"ouid":"1","familyName":"***","paymentMeans":[{"characteristics":{"iban":"***3000"}}],`+
+			`"contactMediums":[{"medium":{"street1":"***","city":"Berlin"}},`+
+			`{"medium":{"type":"PHONE","number":"***56"}}]}`, redacted)
+	})
+
+	t.Run("path rules only match the end of the field path", func(t *testing.T) {
+		r := redact.New(redact.Rule{Path: "medium.*"})
+
+		redacted := r.RedactString(`{"city":"Berlin","medium":{"city":"Berlin","number":1}}`)
+
+		assert.JSONEq(`{"city":"Berlin","medium":{"city":"***","number":"***"}}`, redacted)
+	})
+
+	t.Run("bodies without matches and invalid JSON are returned unchanged", func(t *testing.T) {
+		r := redact.New(redact.DefaultRules()...)
+
+		assert.Equal(`{"b":1, "a":2}`, r.RedactString(`{"b":1, "a":2}`))
+		assert.Equal(`<html>iban</html>`, r.RedactString(`<html>iban</html>`))
+	})
+
+	t.Run("key/value pairs of invalid JSON are masked", func(t *testing.T) {
+		r := redact.New(redact.DefaultRules()...)
+
+		tests := map[string]string{
+			`{"iban":"DE89370400440532013000","city":"Berlin"`:   `{"iban":"***3000","city":"Berlin"`,
+			`{"familyName" : "Muster", "number": 4930123456, "x`: `{"familyName" : "***", "number": ***, "x`,
+			`[{"medium":{"Street1":"Hauptstr. \"1\"","city":`:    `[{"medium":{"Street1":"***","city":`,
+			`{"token":"eyJhbGciOiJIUzI1NiJ9.eyJle`:               `{"token":"***"`,
+		}
+
+		for body, expected := range tests {
+			assert.Equal(expected, r.RedactString(body), body)
+		}
+	})
+}
+
+func TestRedactor_RedactURL(t *testing.T) {
+	assert := assert.New(t)
+	r := redact.New(redact.DefaultRules()...)
+
+	assert.Equal("http://tripica/search?email=%2A%2A%2A&page=1", r.RedactURL("http://tripica/search?email=a@b.de&page=1"))
+	assert.Equal("http://tripica/customer/1", r.RedactURL("http://tripica/customer/1"))
+}
+
+func TestSetDefault(t *testing.T) {
+	assert := assert.New(t)
+	defer redact.SetDefault(redact.New(redact.DefaultRules()...))
+
+	redact.SetDefault(redact.New(redact.Rule{Path: "givenName"}))
+
+	assert.JSONEq(`{"givenName":"***","familyName":"Muster"}`,
+		redact.Default().RedactString(`{"givenName":"Max","familyName":"Muster"}`))
+}
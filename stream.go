@@ -0,0 +1,127 @@
+package tripica
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	gohttp "net/http"
+	"tripica-client/http"
+	"tripica-client/http/errors"
+)
+
+// streamErrorBodyLimit limits how much of an unsuccessful streamed response is read into the returned error.
+const streamErrorBodyLimit = 64 * 1024
+
+// streamArray requests a list endpoint, decoding the elements of the returned JSON array one by one,
+// and passing each of them to fn as soon as it is parsed. The whole body is never held in memory.
+// Streaming stops at the first error returned by fn, or once the context is cancelled.
+// The endpoint is the path template of the URL, used to report schema drift.
+func streamArray[T any](
+	ctx context.Context,
+	httpClient *http.Client,
+	dec *decoder,
+	url, endpoint string,
+	description string,
+	fn func(T) error,
+) error {
+	resp, err := httpClient.Get(url, http.WithContext(ctx), http.StreamResponse())
+	if err != nil {
+		return NewTriPicaError(errors.NewHTTPRequestError(err))
+	}
+
+	body := resp.BodyReader()
+	defer body.Close()
+
+	if resp.StatusCode() == gohttp.StatusNoContent {
+		return nil
+	}
+
+	if resp.StatusCode() != gohttp.StatusOK {
+		b, _ := ioutil.ReadAll(io.LimitReader(body, streamErrorBodyLimit))
+		err := &errors.HTTPError{
+			Body:       string(b),
+			StatusCode: resp.StatusCode(),
+		}
+
+		return NewTriPicaError(fmt.Errorf("couldn't retrieve %s: %w", description, err))
+	}
+
+	decoder := json.NewDecoder(body)
+
+	if err := expectDelim(decoder, '['); err != nil {
+		return NewTriPicaError(errors.NewParseError(err, nil))
+	}
+
+	for decoder.More() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return NewTriPicaError(errors.NewParseError(err, nil))
+		}
+
+		var element T
+		if err := dec.decode(endpoint, raw, &element); err != nil {
+			return NewTriPicaError(errors.NewParseError(err, raw))
+		}
+
+		if err := fn(element); err != nil {
+			return err
+		}
+	}
+
+	if err := expectDelim(decoder, ']'); err != nil {
+		return NewTriPicaError(errors.NewParseError(err, nil))
+	}
+
+	return nil
+}
+
+// expectDelim reads the next JSON token, which is expected to be the provided delimiter.
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if d, ok := token.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("expected %s, got %v", delim, token)
+	}
+
+	return nil
+}
+
+// StreamChannel runs a streaming method in a separate goroutine, sending the streamed elements through
+// the returned channel. The error channel receives the final result once streaming is done, after which
+// both channels are closed. Cancelling the context stops the streaming.
+//
+//	products, errs := StreamChannel(ctx, func(ctx context.Context, fn func(Product) error) error {
+//		return client.StreamProductsByCustomerOUID(ctx, customerOUID, nil, fn)
+//	})
+func StreamChannel[T any](
+	ctx context.Context,
+	stream func(ctx context.Context, fn func(T) error) error,
+) (<-chan T, <-chan error) {
+	elements := make(chan T)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(elements)
+
+		errs <- stream(ctx, func(element T) error {
+			select {
+			case elements <- element:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	return elements, errs
+}
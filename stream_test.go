@@ -0,0 +1,197 @@
+package tripica
+
+import (
+	"context"
+	goerrors "errors"
+	gohttp "net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+	"tripica-client/http/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamHandler writes the first product, and the remaining ones only once released, or once the timeout passed.
+func streamHandler(released <-chan struct{}, written *int32) gohttp.HandlerFunc {
+	return func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		_, _ = w.Write([]byte(`[{"ouid":"A"},`))
+		w.(gohttp.Flusher).Flush()
+
+		select {
+		case <-released:
+		case <-r.Context().Done():
+			return
+		case <-time.After(5 * time.Second):
+		}
+
+		atomic.StoreInt32(written, 1)
+		_, _ = w.Write([]byte(`{"ouid":"B"},{"ouid":"C"}]`))
+	}
+}
+
+func streamProducts(ctx context.Context, client *Client, fn func(Product) error) error {
+	return client.StreamProductsByCustomerOUID(ctx, "C1", nil, fn)
+}
+
+func TestStreamArray(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("elements are decoded one by one", func(t *testing.T) {
+		var written int32
+
+		released := make(chan struct{})
+		client := newTestClient(t, streamHandler(released, &written), nil)
+
+		var ouids []string
+
+		err := streamProducts(context.Background(), client, func(p Product) error {
+			if p.OUID == "A" {
+				assert.Zero(atomic.LoadInt32(&written), "the first product is passed on before the rest is sent")
+				close(released)
+			}
+
+			ouids = append(ouids, p.OUID)
+
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal([]string{"A", "B", "C"}, ouids)
+	})
+
+	t.Run("errors of fn stop the streaming", func(t *testing.T) {
+		client := newTestClient(t, gohttp.HandlerFunc(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+			_, _ = w.Write([]byte(`[{"ouid":"A"},{"ouid":"B"},{"ouid":"C"}]`))
+		}), nil)
+
+		errStop := goerrors.New("stop")
+		calls := 0
+
+		err := streamProducts(context.Background(), client, func(p Product) error {
+			calls++
+
+			return errStop
+		})
+
+		assert.True(goerrors.Is(err, errStop))
+		assert.Equal(1, calls)
+	})
+
+	t.Run("unsuccessful responses are returned as HTTP errors", func(t *testing.T) {
+		client := newTestClient(t, gohttp.HandlerFunc(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+			w.WriteHeader(gohttp.StatusBadGateway)
+			_, _ = w.Write([]byte("upstream unavailable"))
+		}), nil)
+
+		err := streamProducts(context.Background(), client, func(p Product) error {
+			t.Error("no product is expected")
+
+			return nil
+		})
+
+		var httpErr *errors.HTTPError
+		require.True(t, goerrors.As(err, &httpErr))
+		assert.Equal(gohttp.StatusBadGateway, httpErr.StatusCode)
+		assert.Equal("upstream unavailable", httpErr.Body)
+	})
+
+	t.Run("responses without content have no elements", func(t *testing.T) {
+		client := newTestClient(t, gohttp.HandlerFunc(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+			w.WriteHeader(gohttp.StatusNoContent)
+		}), nil)
+
+		err := streamProducts(context.Background(), client, func(p Product) error {
+			t.Error("no product is expected")
+
+			return nil
+		})
+
+		assert.NoError(err)
+	})
+
+	t.Run("bodies other than arrays are rejected", func(t *testing.T) {
+		client := newTestClient(t, gohttp.HandlerFunc(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+			_, _ = w.Write([]byte(`{"ouid":"A"}`))
+		}), nil)
+
+		err := streamProducts(context.Background(), client, func(p Product) error {
+			return nil
+		})
+
+		var httpErr *errors.HTTPError
+		require.True(t, goerrors.As(err, &httpErr))
+		assert.Contains(err.Error(), "couldn't parse response")
+	})
+
+	t.Run("cancelling the context stops the streaming", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var written int32
+
+		client := newTestClient(t, streamHandler(make(chan struct{}), &written), nil)
+
+		var ouids []string
+
+		err := streamProducts(ctx, client, func(p Product) error {
+			ouids = append(ouids, p.OUID)
+			cancel()
+
+			return nil
+		})
+
+		assert.True(goerrors.Is(err, context.Canceled), err)
+		assert.Equal([]string{"A"}, ouids)
+	})
+}
+
+func TestStreamChannel(t *testing.T) {
+	assert := assert.New(t)
+
+	body := gohttp.HandlerFunc(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		_, _ = w.Write([]byte(`[{"ouid":"A"},{"ouid":"B"},{"ouid":"C"}]`))
+	})
+
+	t.Run("elements are sent through the channel", func(t *testing.T) {
+		client := newTestClient(t, body, nil)
+
+		products, errs := StreamChannel(context.Background(), func(ctx context.Context, fn func(Product) error) error {
+			return streamProducts(ctx, client, fn)
+		})
+
+		var ouids []string
+		for p := range products {
+			ouids = append(ouids, p.OUID)
+		}
+
+		assert.NoError(<-errs)
+		assert.Equal([]string{"A", "B", "C"}, ouids)
+	})
+
+	t.Run("cancelling the context stops the sending", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		client := newTestClient(t, body, nil)
+
+		products, errs := StreamChannel(ctx, func(ctx context.Context, fn func(Product) error) error {
+			return streamProducts(ctx, client, fn)
+		})
+
+		first := <-products
+		assert.Equal("A", first.OUID)
+		cancel()
+
+		select {
+		case err := <-errs:
+			assert.True(goerrors.Is(err, context.Canceled), err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("streaming wasn't stopped")
+		}
+
+		_, open := <-products
+		assert.False(open)
+	})
+}
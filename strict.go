@@ -0,0 +1,319 @@
+package tripica
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"tripica-client/log"
+)
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	extensionsType      = reflect.TypeOf(Extensions{})
+)
+
+const (
+	// strictTag marks model fields, e.g. `tripica:"required"`.
+	strictTag         = "tripica"
+	strictTagRequired = "required"
+)
+
+// Possible kinds of schema drift.
+const (
+	// DriftUnknownField is reported for properties returned by triPica which a model doesn't declare.
+	DriftUnknownField DriftKind = "unknown_field"
+	// DriftMissingField is reported for required model fields which triPica didn't return, or returned as null.
+	DriftMissingField DriftKind = "missing_field"
+)
+
+type (
+	// DriftKind represents the kind of difference between a triPica response and the library models.
+	DriftKind string
+
+	// DriftEvent describes a single difference between a triPica response and the library models.
+	// Endpoint is the path template of the request, so that it doesn't contain any identifiers,
+	// while Path is the location of the field within the response, e.g. "contactMediums[].medium.city".
+	DriftEvent struct {
+		Endpoint string
+		Model    string
+		Path     string
+		Kind     DriftKind
+	}
+
+	// StrictMode configures decoding which detects schema drift, i.e. unknown and missing required fields.
+	// Every detected difference is passed to OnDrift, or logged as a warning if OnDrift is nil.
+	// If Fail is true, responses with drift are treated as parse errors.
+	StrictMode struct {
+		OnDrift func(DriftEvent)
+		Fail    bool
+	}
+
+	// SchemaDriftError is returned in strict mode with Fail enabled, if drift was detected.
+	SchemaDriftError struct {
+		Events []DriftEvent
+	}
+
+	// decoder decodes triPica responses, detecting schema drift in strict mode.
+	decoder struct {
+		strict *StrictMode
+		logger log.Logger
+	}
+)
+
+// Error makes SchemaDriftError implement the error interface.
+func (e *SchemaDriftError) Error() string {
+	descriptions := make([]string, 0, len(e.Events))
+	for _, event := range e.Events {
+		descriptions = append(descriptions, fmt.Sprintf("%s %s", event.Kind, event.Path))
+	}
+
+	return fmt.Sprintf("schema drift in %s: %s", e.Events[0].Endpoint, strings.Join(descriptions, ", "))
+}
+
+func newDecoder(strict *StrictMode, logger log.Logger) *decoder {
+	return &decoder{
+		strict: strict,
+		logger: logger,
+	}
+}
+
+// decode decodes the body into v. In strict mode, the body is compared with the type of v,
+// and every difference is reported.
+func (d *decoder) decode(endpoint string, body []byte, v interface{}) error {
+	if err := json.Unmarshal(body, v); err != nil {
+		return err
+	}
+
+	if d == nil || d.strict == nil {
+		return nil
+	}
+
+	var raw interface{}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return err
+	}
+
+	t := reflect.TypeOf(v)
+	events := detectDrift(endpoint, modelName(t), t, raw, "", nil)
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	for _, event := range events {
+		d.report(event)
+	}
+
+	if d.strict.Fail {
+		return &SchemaDriftError{Events: events}
+	}
+
+	return nil
+}
+
+func (d *decoder) report(event DriftEvent) {
+	if d.strict.OnDrift != nil {
+		d.strict.OnDrift(event)
+
+		return
+	}
+
+	if d.logger == nil {
+		return
+	}
+
+	d.logger.WithFields(map[string]interface{}{
+		"endpoint": event.Endpoint,
+		"model":    event.Model,
+		"path":     event.Path,
+		"kind":     string(event.Kind),
+	}).Warn("triPica schema drift detected")
+}
+
+// detectDrift walks through the decoded JSON value alongside the type it was decoded into.
+func detectDrift(
+	endpoint, model string,
+	t reflect.Type,
+	raw interface{},
+	path string,
+	events []DriftEvent,
+) []DriftEvent {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if isLeafType(t) {
+		return events
+	}
+
+	switch value := raw.(type) {
+	case []interface{}:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return events
+		}
+
+		for _, element := range value {
+			events = detectDrift(endpoint, model, t.Elem(), element, path+"[]", events)
+		}
+	case map[string]interface{}:
+		if t.Kind() != reflect.Struct {
+			return events
+		}
+
+		events = detectStructDrift(endpoint, model, t, value, path, events)
+	}
+
+	return events
+}
+
+func detectStructDrift(
+	endpoint, model string,
+	t reflect.Type,
+	object map[string]interface{},
+	path string,
+	events []DriftEvent,
+) []DriftEvent {
+	fields := map[string]reflect.StructField{}
+	collectStructFields(t, fields)
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fieldPath := joinPath(path, key)
+
+		field, ok := fields[strings.ToLower(key)]
+		if !ok {
+			events = append(events, DriftEvent{Endpoint: endpoint, Model: model, Path: fieldPath, Kind: DriftUnknownField})
+
+			continue
+		}
+
+		events = detectDrift(endpoint, model, field.Type, object[key], fieldPath, events)
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		field := fields[name]
+		if field.Tag.Get(strictTag) != strictTagRequired {
+			continue
+		}
+
+		if value, ok := lookupKey(object, name); !ok || value == nil {
+			events = append(events, DriftEvent{
+				Endpoint: endpoint,
+				Model:    model,
+				Path:     joinPath(path, jsonName(field)),
+				Kind:     DriftMissingField,
+			})
+		}
+	}
+
+	return events
+}
+
+// collectStructFields collects the fields of the struct type by their lower cased JSON names.
+// Fields of embedded structs without a JSON name are collected as if they were declared by the struct itself.
+func collectStructFields(t reflect.Type, fields map[string]reflect.StructField) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := jsonName(f)
+
+		if name == "-" {
+			continue
+		}
+
+		if f.Anonymous && f.Tag.Get("json") == "" && f.Type.Kind() == reflect.Struct {
+			collectStructFields(f.Type, fields)
+
+			continue
+		}
+
+		if f.PkgPath != "" {
+			continue
+		}
+
+		fields[strings.ToLower(name)] = f
+	}
+}
+
+func jsonName(f reflect.StructField) string {
+	if name := strings.Split(f.Tag.Get("json"), ",")[0]; name != "" {
+		return name
+	}
+
+	return f.Name
+}
+
+// lookupKey finds the value of the key, ignoring case like encoding/json does.
+func lookupKey(object map[string]interface{}, key string) (interface{}, bool) {
+	for k, v := range object {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+// isLeafType determines whether the type decodes a JSON value on its own, e.g. Date, time.Time or Money,
+// so that the value's contents are not compared with the type's fields. Models retaining undeclared properties
+// in their Extensions are decoded field by field, and are therefore no leaves.
+func isLeafType(t reflect.Type) bool {
+	if hasExtensions(t) {
+		return false
+	}
+
+	for _, u := range []reflect.Type{jsonUnmarshalerType, textUnmarshalerType} {
+		if t.Implements(u) || reflect.PtrTo(t).Implements(u) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasExtensions determines whether the type is a model retaining undeclared properties.
+func hasExtensions(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Type == extensionsType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func modelName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+
+	return t.Name()
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
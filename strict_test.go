@@ -0,0 +1,165 @@
+package tripica
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"reflect"
+	"testing"
+	"time"
+	"tripica-client/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	driftModel struct {
+		OUID    string      `json:"ouid" tripica:"required"`
+		Name    string      `json:"name" tripica:"required"`
+		Created Date        `json:"created"`
+		Updated time.Time   `json:"updated"`
+		Amount  Money       `json:"amount"`
+		Status  driftStatus `json:"status"`
+		Items   []driftItem `json:"items"`
+	}
+
+	driftItem struct {
+		ID string `json:"id" tripica:"required"`
+	}
+
+	// driftStatus decodes an object on its own, keeping only its code.
+	driftStatus string
+)
+
+func (s *driftStatus) UnmarshalJSON(data []byte) error {
+	var status struct{ Code string }
+	if err := json.Unmarshal(data, &status); err != nil {
+		return err
+	}
+
+	*s = driftStatus(status.Code)
+
+	return nil
+}
+
+func TestDecoder_Decode(t *testing.T) {
+	const endpoint = "/model/%s"
+
+	tests := []struct {
+		name   string
+		body   string
+		events []DriftEvent
+	}{
+		{
+			name: "matching body",
+			body: `{"ouid":"1","name":"a","created":1600000000000,"updated":"2020-09-13T12:26:40Z","amount":100,` +
+				`"status":{"code":"ACTIVE","reason":"new"},"items":[{"id":"1"}]}`,
+		},
+		{
+			name: "unknown fields",
+			body: `{"ouid":"1","name":"a","color":"red","items":[{"id":"1","size":2}]}`,
+			events: []DriftEvent{
+				{Endpoint: endpoint, Model: "driftModel", Path: "color", Kind: DriftUnknownField},
+				{Endpoint: endpoint, Model: "driftModel", Path: "items[].size", Kind: DriftUnknownField},
+			},
+		},
+		{
+			name: "missing and null required fields",
+			body: `{"name":null,"items":[{}]}`,
+			events: []DriftEvent{
+				{Endpoint: endpoint, Model: "driftModel", Path: "items[].id", Kind: DriftMissingField},
+				{Endpoint: endpoint, Model: "driftModel", Path: "name", Kind: DriftMissingField},
+				{Endpoint: endpoint, Model: "driftModel", Path: "ouid", Kind: DriftMissingField},
+			},
+		},
+		{
+			name: "fields are matched case-insensitively",
+			body: `{"OUID":"1","Name":"a"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			var events []DriftEvent
+
+			d := newDecoder(&StrictMode{OnDrift: func(e DriftEvent) { events = append(events, e) }}, nil)
+
+			var model *driftModel
+			require.NoError(t, d.decode(endpoint, []byte(tt.body), &model))
+			assert.Equal(t, tt.events, events)
+		})
+	}
+}
+
+func TestDecoder_DecodeModes(t *testing.T) {
+	assert := assert.New(t)
+
+	body := []byte(`[{"ouid":"1","name":"a","color":"red"}]`)
+
+	t.Run("models with extensions are checked field by field", func(t *testing.T) {
+		var events []DriftEvent
+
+		d := newDecoder(&StrictMode{OnDrift: func(e DriftEvent) { events = append(events, e) }}, nil)
+
+		var charges []*SettlementNoteAdvice
+		assert.NoError(d.decode("/settlement", []byte(`[{"ouid":"1","id":"2","billDate":1600000000000,"x":1}]`),
+			&charges))
+
+		assert.Equal([]DriftEvent{{
+			Endpoint: "/settlement", Model: "SettlementNoteAdvice", Path: "[].x", Kind: DriftUnknownField,
+		}}, events)
+		assert.True(charges[0].Extensions.Has("x"))
+	})
+
+	t.Run("drift fails decoding if configured", func(t *testing.T) {
+		d := newDecoder(&StrictMode{Fail: true}, nil)
+
+		var models []driftModel
+		err := d.decode("/model", body, &models)
+
+		var driftErr *SchemaDriftError
+		assert.True(goerrors.As(err, &driftErr))
+		assert.Equal("schema drift in /model: unknown_field [].color", err.Error())
+	})
+
+	t.Run("drift is logged without a handler", func(t *testing.T) {
+		logger := log.NewCapturingLogger()
+		d := newDecoder(&StrictMode{}, logger)
+
+		var models []driftModel
+		assert.NoError(d.decode("/model", body, &models))
+
+		logger.AssertLogged(t, log.LevelWarn, "triPica schema drift detected", map[string]interface{}{
+			"path": "[].color",
+			"kind": string(DriftUnknownField),
+		})
+	})
+
+	t.Run("drift isn't detected without strict mode", func(t *testing.T) {
+		var models []driftModel
+		assert.NoError(newDecoder(nil, nil).decode("/model", body, &models))
+		assert.Equal("a", models[0].Name)
+	})
+}
+
+func TestIsLeafType(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		leaf  bool
+	}{
+		{Date{}, true},
+		{time.Time{}, true},
+		{Money{}, true},
+		{driftStatus(""), true},
+		{json.RawMessage{}, true},
+		{driftModel{}, false},
+		{Customer{}, false},
+		{SettlementNoteAdvice{}, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.leaf, isLeafType(reflect.TypeOf(tt.value)), "%T", tt.value)
+	}
+}
@@ -0,0 +1,184 @@
+package tripica
+
+import (
+	"sort"
+	"time"
+)
+
+type (
+	// ValidityPeriod represents the period a temporal resource is valid in, including its Start and excluding
+	// its End. A zero Start leaves the period open towards the past, and a zero End towards the future.
+	ValidityPeriod struct {
+		Start time.Time
+		End   time.Time
+	}
+
+	// ContactMediumTimeline orders the contact mediums of a single type chronologically, so that the medium valid
+	// at any time, e.g. the delivery address on the date a letter was sent, can be determined, and inconsistencies
+	// of their validity periods detected.
+	ContactMediumTimeline struct {
+		Type    ContactMediumType
+		mediums []ContactMedium
+		periods []ValidityPeriod
+	}
+
+	// ContactMediumOverlap describes two contact mediums of the same type which are valid at the same time.
+	ContactMediumOverlap struct {
+		First   ContactMedium
+		Second  ContactMedium
+		Overlap ValidityPeriod
+	}
+)
+
+// Validity returns the period the contact medium is valid in.
+func (c *ContactMedium) Validity() ValidityPeriod {
+	return ValidityPeriod{Start: c.StartDateTime.Time, End: c.EndDateTime.Time}
+}
+
+// Contains determines whether the time lies within the period.
+func (p ValidityPeriod) Contains(t time.Time) bool {
+	return (p.Start.IsZero() || !t.Before(p.Start)) && (p.End.IsZero() || t.Before(p.End))
+}
+
+// IsEmpty determines whether the period doesn't contain any time, e.g. because a contact medium was ended
+// at its start to revoke it.
+func (p ValidityPeriod) IsEmpty() bool {
+	return !p.Start.IsZero() && !p.End.IsZero() && !p.End.After(p.Start)
+}
+
+// Intersect returns the period contained in both periods, and whether they overlap at all.
+func (p ValidityPeriod) Intersect(other ValidityPeriod) (ValidityPeriod, bool) {
+	intersection := p
+
+	if intersection.Start.IsZero() || other.Start.After(intersection.Start) {
+		intersection.Start = other.Start
+	}
+
+	if intersection.End.IsZero() || (!other.End.IsZero() && other.End.Before(intersection.End)) {
+		intersection.End = other.End
+	}
+
+	return intersection, !intersection.IsEmpty()
+}
+
+// Timeline returns the timeline of the individual's contact mediums of the type. Revoked contact mediums, whose
+// validity periods are empty, are left out.
+func (i *Individual) Timeline(typ ContactMediumType) *ContactMediumTimeline {
+	t := &ContactMediumTimeline{Type: typ}
+
+	for _, cm := range i.ContactMediums {
+		if cm.Type == typ && !cm.Validity().IsEmpty() {
+			t.mediums = append(t.mediums, cm)
+		}
+	}
+
+	sort.SliceStable(t.mediums, func(a, b int) bool {
+		return t.mediums[a].StartDateTime.Before(t.mediums[b].StartDateTime.Time)
+	})
+
+	for _, cm := range t.mediums {
+		t.periods = append(t.periods, cm.Validity())
+	}
+
+	return t
+}
+
+// DeliveryAddressAt returns the delivery address valid at the provided time, which may lie in the past.
+func (i *Individual) DeliveryAddressAt(at time.Time) (MediumTypeAddress, bool) {
+	return i.Timeline(ContactMediumTypeDeliveryAddress).addressAt(at)
+}
+
+// BillingAddressAt returns the billing address valid at the provided time, which may lie in the past.
+func (i *Individual) BillingAddressAt(at time.Time) (MediumTypeAddress, bool) {
+	return i.Timeline(ContactMediumTypeBillingAddress).addressAt(at)
+}
+
+// At returns the contact medium valid at the provided time, or false if there is none. If several contact mediums
+// overlap at that time, the most recently started one is returned, preferring preferred ones if they started
+// at the same time.
+func (t *ContactMediumTimeline) At(at time.Time) (ContactMedium, bool) {
+	found := -1
+
+	for j, period := range t.periods {
+		if !period.Contains(at) {
+			continue
+		}
+
+		if found < 0 || t.mediums[j].StartDateTime.After(t.mediums[found].StartDateTime.Time) ||
+			(t.mediums[j].StartDateTime.Equal(t.mediums[found].StartDateTime.Time) && t.mediums[j].Preferred) {
+			found = j
+		}
+	}
+
+	if found < 0 {
+		return ContactMedium{}, false
+	}
+
+	return t.mediums[found], true
+}
+
+// History returns the contact mediums ordered by their start, the oldest first.
+func (t *ContactMediumTimeline) History() []ContactMedium {
+	history := make([]ContactMedium, len(t.mediums))
+	copy(history, t.mediums)
+
+	return history
+}
+
+// Gaps returns the periods between the start of the first contact medium and the end of the last one
+// in which no contact medium is valid.
+func (t *ContactMediumTimeline) Gaps() []ValidityPeriod {
+	var (
+		gaps    []ValidityPeriod
+		covered time.Time
+	)
+
+	for j, period := range t.periods {
+		if j > 0 && period.Start.After(covered) {
+			gaps = append(gaps, ValidityPeriod{Start: covered, End: period.Start})
+		}
+
+		if period.End.IsZero() {
+			break
+		}
+
+		if j == 0 || period.End.After(covered) {
+			covered = period.End
+		}
+	}
+
+	return gaps
+}
+
+// Overlaps returns every pair of contact mediums which are valid at the same time.
+func (t *ContactMediumTimeline) Overlaps() []ContactMediumOverlap {
+	var overlaps []ContactMediumOverlap
+
+	for a := range t.periods {
+		for b := a + 1; b < len(t.periods); b++ {
+			if overlap, ok := t.periods[a].Intersect(t.periods[b]); ok {
+				overlaps = append(overlaps, ContactMediumOverlap{
+					First:   t.mediums[a],
+					Second:  t.mediums[b],
+					Overlap: overlap,
+				})
+			}
+		}
+	}
+
+	return overlaps
+}
+
+// IsConsistent determines whether exactly one contact medium is valid at any time since the first one started.
+func (t *ContactMediumTimeline) IsConsistent() bool {
+	return len(t.Gaps()) == 0 && len(t.Overlaps()) == 0
+}
+
+func (t *ContactMediumTimeline) addressAt(at time.Time) (MediumTypeAddress, bool) {
+	cm, ok := t.At(at)
+	if !ok {
+		return MediumTypeAddress{}, false
+	}
+
+	return cm.Medium.Address()
+}
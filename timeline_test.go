@@ -0,0 +1,341 @@
+package tripica
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// day returns the day of January 2024, or the zero time for 0, which leaves a period open.
+func day(n int) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+
+	return time.Date(2024, time.January, n, 0, 0, 0, 0, time.UTC)
+}
+
+func period(start, end int) ValidityPeriod {
+	return ValidityPeriod{Start: day(start), End: day(end)}
+}
+
+// deliveryAddress returns a delivery address valid from the start until the end day.
+func deliveryAddress(ouid string, start, end int, preferred bool) ContactMedium {
+	return ContactMedium{
+		OUID:          ouid,
+		Preferred:     preferred,
+		Type:          ContactMediumTypeDeliveryAddress,
+		StartDateTime: NewDate(day(start)),
+		EndDateTime:   NewDate(day(end)),
+		Medium:        Medium{MediumTypeAddress: MediumTypeAddress{City: ouid}},
+	}
+}
+
+func deliveryTimeline(mediums ...ContactMedium) *ContactMediumTimeline {
+	return (&Individual{ContactMediums: mediums}).Timeline(ContactMediumTypeDeliveryAddress)
+}
+
+func ouids(mediums []ContactMedium) []string {
+	result := make([]string, 0, len(mediums))
+	for _, cm := range mediums {
+		result = append(result, cm.OUID)
+	}
+
+	return result
+}
+
+func TestValidityPeriod_Intersect(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     ValidityPeriod
+		want     ValidityPeriod
+		overlaps bool
+	}{
+		{name: "overlapping", a: period(1, 10), b: period(5, 15), want: period(5, 10), overlaps: true},
+		{name: "contained", a: period(1, 10), b: period(3, 4), want: period(3, 4), overlaps: true},
+		{name: "disjoint", a: period(1, 5), b: period(6, 10), overlaps: false},
+		{name: "adjacent", a: period(1, 5), b: period(5, 10), overlaps: false},
+		{name: "zero start", a: period(0, 10), b: period(5, 15), want: period(5, 10), overlaps: true},
+		{name: "both zero starts", a: period(0, 10), b: period(0, 15), want: period(0, 10), overlaps: true},
+		{name: "zero end", a: period(1, 0), b: period(5, 15), want: period(5, 15), overlaps: true},
+		{name: "both zero ends", a: period(1, 0), b: period(5, 0), want: period(5, 0), overlaps: true},
+		{name: "unbounded", a: period(0, 0), b: period(5, 15), want: period(5, 15), overlaps: true},
+		{name: "zero end before start", a: period(10, 0), b: period(1, 5), overlaps: false},
+		{name: "revoked", a: period(5, 5), b: period(1, 10), overlaps: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			for _, args := range [][2]ValidityPeriod{{tt.a, tt.b}, {tt.b, tt.a}} {
+				intersection, ok := args[0].Intersect(args[1])
+
+				assert.Equal(t, tt.overlaps, ok)
+
+				if tt.overlaps {
+					assert.Equal(t, tt.want, intersection)
+				}
+			}
+		})
+	}
+}
+
+func TestValidityPeriod_Contains(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(period(1, 10).Contains(day(1)), "the start is included")
+	assert.False(period(1, 10).Contains(day(10)), "the end is excluded")
+	assert.True(period(0, 10).Contains(day(1).AddDate(-10, 0, 0)))
+	assert.True(period(1, 0).Contains(day(1).AddDate(10, 0, 0)))
+	assert.False(period(5, 5).Contains(day(5)))
+}
+
+func TestContactMedium_IsValidAt(t *testing.T) {
+	assert := assert.New(t)
+
+	cm := deliveryAddress("A", 1, 10, false)
+
+	for _, n := range []int{1, 5, 10, 11} {
+		assert.Equal(cm.Validity().Contains(day(n)), cm.IsValidAt(day(n)), "day %d", n)
+	}
+
+	assert.True(cm.IsValidAt(day(1)), "the start is included")
+	assert.False(cm.IsValidAt(day(10)), "the end is excluded")
+}
+
+func TestContactMediumTimeline_At(t *testing.T) {
+	tests := []struct {
+		name    string
+		mediums []ContactMedium
+		at      time.Time
+		want    string
+	}{
+		{
+			name:    "none before the first start",
+			mediums: []ContactMedium{deliveryAddress("A", 5, 0, false)},
+			at:      day(4),
+		},
+		{
+			name:    "the start is included",
+			mediums: []ContactMedium{deliveryAddress("A", 1, 5, false), deliveryAddress("B", 5, 0, false)},
+			at:      day(5),
+			want:    "B",
+		},
+		{
+			name:    "the end is excluded",
+			mediums: []ContactMedium{deliveryAddress("A", 1, 5, false)},
+			at:      day(5),
+		},
+		{
+			name:    "zero start",
+			mediums: []ContactMedium{deliveryAddress("A", 0, 5, false), deliveryAddress("B", 5, 0, false)},
+			at:      day(1).AddDate(-30, 0, 0),
+			want:    "A",
+		},
+		{
+			name:    "zero end",
+			mediums: []ContactMedium{deliveryAddress("A", 1, 0, false)},
+			at:      day(1).AddDate(30, 0, 0),
+			want:    "A",
+		},
+		{
+			name:    "overlaps resolve to the most recent start",
+			mediums: []ContactMedium{deliveryAddress("B", 3, 0, false), deliveryAddress("A", 1, 0, true)},
+			at:      day(4),
+			want:    "B",
+		},
+		{
+			name:    "equal starts prefer the preferred medium",
+			mediums: []ContactMedium{deliveryAddress("A", 1, 0, true), deliveryAddress("B", 1, 0, false)},
+			at:      day(4),
+			want:    "A",
+		},
+		{
+			name:    "equal starts prefer the preferred medium regardless of their order",
+			mediums: []ContactMedium{deliveryAddress("B", 1, 0, false), deliveryAddress("A", 1, 0, true)},
+			at:      day(4),
+			want:    "A",
+		},
+		{
+			name:    "revoked mediums are ignored",
+			mediums: []ContactMedium{deliveryAddress("A", 1, 0, false), deliveryAddress("B", 3, 3, true)},
+			at:      day(3),
+			want:    "A",
+		},
+		{
+			name: "other types are ignored",
+			mediums: []ContactMedium{
+				{OUID: "A", Type: ContactMediumTypeBillingAddress, StartDateTime: NewDate(day(1))},
+			},
+			at: day(3),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			cm, ok := deliveryTimeline(tt.mediums...).At(tt.at)
+
+			assert.Equal(t, tt.want != "", ok)
+			assert.Equal(t, tt.want, cm.OUID)
+		})
+	}
+}
+
+func TestContactMediumTimeline_History(t *testing.T) {
+	timeline := deliveryTimeline(
+		deliveryAddress("C", 10, 0, false),
+		deliveryAddress("R", 4, 4, false),
+		deliveryAddress("A", 0, 5, false),
+		deliveryAddress("B", 5, 10, false),
+	)
+
+	assert.Equal(t, []string{"A", "B", "C"}, ouids(timeline.History()))
+}
+
+func TestContactMediumTimeline_Gaps(t *testing.T) {
+	tests := []struct {
+		name    string
+		mediums []ContactMedium
+		want    []ValidityPeriod
+	}{
+		{
+			name: "none",
+		},
+		{
+			name:    "consecutive",
+			mediums: []ContactMedium{deliveryAddress("A", 1, 5, false), deliveryAddress("B", 5, 0, false)},
+		},
+		{
+			name:    "between mediums",
+			mediums: []ContactMedium{deliveryAddress("A", 1, 5, false), deliveryAddress("B", 8, 0, false)},
+			want:    []ValidityPeriod{period(5, 8)},
+		},
+		{
+			name: "zero start",
+			mediums: []ContactMedium{
+				deliveryAddress("A", 0, 5, false),
+				deliveryAddress("B", 8, 10, false),
+				deliveryAddress("C", 12, 0, false),
+			},
+			want: []ValidityPeriod{period(5, 8), period(10, 12)},
+		},
+		{
+			name: "zero end covers later starts",
+			mediums: []ContactMedium{
+				deliveryAddress("A", 1, 0, false),
+				deliveryAddress("B", 8, 10, false),
+				deliveryAddress("C", 12, 0, false),
+			},
+		},
+		{
+			name: "nested periods",
+			mediums: []ContactMedium{
+				deliveryAddress("A", 1, 10, false),
+				deliveryAddress("B", 2, 4, false),
+				deliveryAddress("C", 6, 12, false),
+			},
+		},
+		{
+			name: "equal starts",
+			mediums: []ContactMedium{
+				deliveryAddress("A", 1, 5, true),
+				deliveryAddress("B", 1, 3, false),
+				deliveryAddress("C", 6, 0, false),
+			},
+			want: []ValidityPeriod{period(5, 6)},
+		},
+		{
+			name: "revoked mediums don't fill gaps",
+			mediums: []ContactMedium{
+				deliveryAddress("A", 1, 5, false),
+				deliveryAddress("R", 5, 5, false),
+				deliveryAddress("B", 8, 0, false),
+			},
+			want: []ValidityPeriod{period(5, 8)},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, deliveryTimeline(tt.mediums...).Gaps())
+		})
+	}
+}
+
+func TestContactMediumTimeline_Overlaps(t *testing.T) {
+	type overlap struct {
+		first, second string
+		period        ValidityPeriod
+	}
+
+	tests := []struct {
+		name       string
+		mediums    []ContactMedium
+		want       []overlap
+		consistent bool
+	}{
+		{
+			name:       "consecutive",
+			mediums:    []ContactMedium{deliveryAddress("A", 1, 5, false), deliveryAddress("B", 5, 0, false)},
+			consistent: true,
+		},
+		{
+			name:    "bounded",
+			mediums: []ContactMedium{deliveryAddress("A", 1, 6, false), deliveryAddress("B", 5, 10, false)},
+			want:    []overlap{{"A", "B", period(5, 6)}},
+		},
+		{
+			name:    "zero start",
+			mediums: []ContactMedium{deliveryAddress("A", 0, 6, false), deliveryAddress("B", 0, 3, false)},
+			want:    []overlap{{"A", "B", period(0, 3)}},
+		},
+		{
+			name:    "zero end",
+			mediums: []ContactMedium{deliveryAddress("A", 1, 0, false), deliveryAddress("B", 5, 0, false)},
+			want:    []overlap{{"A", "B", period(5, 0)}},
+		},
+		{
+			name: "equal starts",
+			mediums: []ContactMedium{
+				deliveryAddress("A", 1, 0, false),
+				deliveryAddress("B", 1, 0, true),
+				deliveryAddress("C", 3, 0, false),
+			},
+			want: []overlap{
+				{"A", "B", period(1, 0)},
+				{"A", "C", period(3, 0)},
+				{"B", "C", period(3, 0)},
+			},
+		},
+		{
+			name: "revoked mediums don't overlap",
+			mediums: []ContactMedium{
+				deliveryAddress("A", 1, 0, false),
+				deliveryAddress("R", 5, 5, false),
+			},
+			consistent: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			timeline := deliveryTimeline(tt.mediums...)
+
+			var got []overlap
+			for _, o := range timeline.Overlaps() {
+				got = append(got, overlap{o.First.OUID, o.Second.OUID, o.Overlap})
+			}
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.consistent, timeline.IsConsistent())
+		})
+	}
+}